@@ -0,0 +1,95 @@
+package gogojson
+
+import (
+	"context"
+)
+
+// asyncBuffer is the number of tokens the lexer may run ahead of the parser
+const asyncBuffer = 64
+
+// TokenizeAsync runs the lexer in its own goroutine and sends the tokens as
+// they are read. The token channel is closed at the end of input or on the
+// first error, which is sent on the error channel before. Both channels are
+// closed once the goroutine is done. Cancel ctx to stop the lexer when the
+// tokens aren't read to the end
+func TokenizeAsync(ctx context.Context, source Iterator) (<-chan Token, <-chan error) {
+	tokens := make(chan Token, asyncBuffer)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(tokens)
+
+		iter := lexer{source}
+		for {
+			if err := ctx.Err(); err != nil {
+				errs <- err
+				return
+			}
+
+			token, err := iter.nextToken()
+			if err != nil {
+				errs <- err
+				return
+			} else if token == nil {
+				return
+			}
+
+			select {
+			case tokens <- *token:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return tokens, errs
+}
+
+// chanSource is a tokenSource over the channels of TokenizeAsync
+type chanSource struct {
+	tokens <-chan Token
+	errs   <-chan error
+	peeked *Token
+	err    error
+}
+
+func (source *chanSource) next() (*Token, error) {
+	token, err := source.peek()
+	source.peeked = nil
+
+	return token, err
+}
+
+func (source *chanSource) peek() (*Token, error) {
+	if source.peeked != nil || source.err != nil {
+		return source.peeked, source.err
+	}
+
+	token, ok := <-source.tokens
+	if !ok {
+		// nil if the lexer reached the end of input
+		source.err = <-source.errs
+		return nil, source.err
+	}
+
+	source.peeked = &token
+	return source.peeked, nil
+}
+
+// ParseAsync parses a document of any type while it is being tokenized in
+// another goroutine. The lexer is stopped as soon as parsing fails or ctx is
+// done
+func ParseAsync(ctx context.Context, source Iterator) (interface{}, error) {
+	return ParseOptions{}.ParseAsync(ctx, source)
+}
+
+// ParseAsync works like the package level ParseAsync, but applies the options
+func (opts ParseOptions) ParseAsync(ctx context.Context, source Iterator) (interface{}, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tokens, errs := TokenizeAsync(ctx, source)
+	return opts.parseDocument(&chanSource{tokens: tokens, errs: errs})
+}
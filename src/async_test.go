@@ -0,0 +1,100 @@
+package gogojson
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenizeAsync(t *testing.T) {
+	assert := assert.New(t)
+	expected, err := Tokenize(MakeIterator(parseJSON))
+	assert.Nil(err)
+
+	tokens, errs := TokenizeAsync(context.Background(), MakeIterator(parseJSON))
+	i := 0
+	for token := range tokens {
+		assert.Equal(*expected[i], token)
+		i++
+	}
+
+	assert.Equal(len(expected), i)
+	assert.Nil(<-errs)
+}
+
+func TestTokenizeAsyncError(t *testing.T) {
+	assert := assert.New(t)
+	tokens, errs := TokenizeAsync(context.Background(), MakeIterator(`[1, !]`))
+
+	count := 0
+	for range tokens {
+		count++
+	}
+
+	assert.Equal(3, count)
+	assert.Equal(&SyntaxError{
+		Msg:      "Unexpected character type: '!'",
+		Position: Position{Line: 1, Column: 5, Offset: 4},
+	}, <-errs)
+}
+
+func TestParseAsync(t *testing.T) {
+	assert := assert.New(t)
+	parsed, err := ParseAsync(context.Background(), MakeReaderIterator(strings.NewReader(arrayJSON)))
+	assert.Nil(err)
+
+	expected, _ := GogoJson(arrayJSON)
+	assert.Equal(expected, parsed)
+
+	parsed, err = ParseOptions{Numbers: NumberInt64}.ParseAsync(context.Background(), MakeIterator(`[9007199254740993]`))
+	assert.Nil(err)
+	assert.Equal([]interface{}{int64(9007199254740993)}, parsed)
+}
+
+func TestParseAsyncErrors(t *testing.T) {
+	assert := assert.New(t)
+	_, err := ParseAsync(context.Background(), MakeIterator(`{"a": [1, 2}`))
+	assert.Equal(&SyntaxError{
+		Msg:      "Expected punctuation with value ',', instead got: '}'",
+		Position: Position{Line: 1, Column: 12, Offset: 11},
+		Pointer:  Pointer{"a"},
+	}, err)
+
+	_, err = ParseAsync(context.Background(), MakeIterator(`{"a": [1, 2`))
+	assert.Equal(&SyntaxError{
+		Msg:      "Unexpected end of input",
+		Position: Position{Line: 1, Column: 11, Offset: 10},
+		Pointer:  Pointer{"a"},
+	}, err)
+
+	_, err = ParseAsync(context.Background(), MakeIterator(`{"a": tru}`))
+	assert.IsType(&SyntaxError{}, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ParseAsync(ctx, MakeIterator(parseJSON))
+	assert.Equal(context.Canceled, err)
+}
+
+func TestParseAsyncNoLeak(t *testing.T) {
+	assert := assert.New(t)
+	before := runtime.NumGoroutine()
+
+	// the parser fails right away while the lexer still has plenty to send
+	long := `[1, 2} ` + strings.Repeat(`"filler", `, 10*asyncBuffer)
+	for i := 0; i < 20; i++ {
+		_, err := ParseAsync(context.Background(), MakeIterator(long))
+		assert.NotNil(err)
+	}
+
+	// cancellation is asynchronous, give the lexers a moment to finish
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(before, runtime.NumGoroutine())
+}
@@ -0,0 +1,376 @@
+package gogojson
+
+import (
+	"encoding"
+	"encoding/base64"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+var numberType = reflect.TypeOf(Number(""))
+
+// decoder assigns generic values, as produced by the parser in NumberString
+// mode, to go values of any type
+type decoder struct {
+	// numbers is the representation of numbers stored in interface values
+	numbers NumberMode
+}
+
+func (dec *decoder) decode(path string, src interface{}, dst reflect.Value) error {
+	u, textU := unmarshalers(dst)
+	if u != nil {
+		return dec.callUnmarshaler(path, src, dst, u)
+	}
+
+	if src == nil {
+		// null only resets values that can be nil
+		switch dst.Kind() {
+		case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+			dst.Set(reflect.Zero(dst.Type()))
+		}
+		return nil
+	}
+
+	if dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return dec.decode(path, src, dst.Elem())
+	} else if dst.Kind() == reflect.Interface {
+		if dst.NumMethod() > 0 {
+			return typeError(path, src, dst.Type())
+		}
+		dst.Set(reflect.ValueOf(dec.generic(src)))
+		return nil
+	} else if literal, ok := src.(Number); ok && dst.Type() == bigFloatType.Elem() {
+		// big.Float only unmarshals text, so its numbers are parsed here
+		return dec.decodeBigFloat(path, literal, dst)
+	} else if textU != nil {
+		return dec.callTextUnmarshaler(path, src, dst, textU)
+	}
+
+	switch value := src.(type) {
+	case bool:
+		if dst.Kind() != reflect.Bool {
+			return typeError(path, src, dst.Type())
+		}
+		dst.SetBool(value)
+	case string:
+		return dec.decodeString(path, value, dst)
+	case Number:
+		return dec.decodeNumber(path, value, dst)
+	case []interface{}:
+		return dec.decodeArray(path, value, dst)
+	case map[string]interface{}:
+		return dec.decodeObject(path, value, dst)
+	}
+
+	return nil
+}
+
+func (dec *decoder) decodeString(path string, src string, dst reflect.Value) error {
+	if dst.Kind() == reflect.String && dst.Type() != numberType {
+		dst.SetString(src)
+		return nil
+	}
+
+	// byte slices are encoded as base64 strings
+	if dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8 {
+		decoded, err := base64.StdEncoding.DecodeString(src)
+		if err != nil {
+			return typeError(path, src, dst.Type())
+		}
+		dst.SetBytes(decoded)
+		return nil
+	}
+
+	return typeError(path, src, dst.Type())
+}
+
+func (dec *decoder) decodeNumber(path string, src Number, dst reflect.Value) error {
+	literal := string(src)
+
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(literal, 10, 64)
+		if err != nil || dst.OverflowInt(i) {
+			return typeError(path, src, dst.Type())
+		}
+		dst.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(literal, 10, 64)
+		if err != nil || dst.OverflowUint(u) {
+			return typeError(path, src, dst.Type())
+		}
+		dst.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(literal, dst.Type().Bits())
+		if err != nil {
+			return typeError(path, src, dst.Type())
+		}
+		dst.SetFloat(f)
+	case reflect.String:
+		if dst.Type() != numberType {
+			return typeError(path, src, dst.Type())
+		}
+		dst.SetString(literal)
+	default:
+		return typeError(path, src, dst.Type())
+	}
+
+	return nil
+}
+
+func (dec *decoder) decodeBigFloat(path string, src Number, dst reflect.Value) error {
+	f, err := bigFloat(string(src))
+	if err != nil {
+		return typeError(path, src, dst.Type())
+	}
+	dst.Set(reflect.ValueOf(*f))
+	return nil
+}
+
+func (dec *decoder) decodeArray(path string, src []interface{}, dst reflect.Value) error {
+	switch dst.Kind() {
+	case reflect.Slice:
+		slice := reflect.MakeSlice(dst.Type(), len(src), len(src))
+		for i, value := range src {
+			if err := dec.decode(pathIndex(path, i), value, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(slice)
+	case reflect.Array:
+		// surplus elements are dropped, missing ones zeroed
+		for i := 0; i < dst.Len(); i++ {
+			if i >= len(src) {
+				dst.Index(i).Set(reflect.Zero(dst.Type().Elem()))
+			} else if err := dec.decode(pathIndex(path, i), src[i], dst.Index(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return typeError(path, src, dst.Type())
+	}
+
+	return nil
+}
+
+func (dec *decoder) decodeObject(path string, src map[string]interface{}, dst reflect.Value) error {
+	switch dst.Kind() {
+	case reflect.Struct:
+		return dec.decodeStruct(path, src, dst)
+	case reflect.Map:
+		return dec.decodeMap(path, src, dst)
+	}
+
+	return typeError(path, src, dst.Type())
+}
+
+func (dec *decoder) decodeStruct(path string, src map[string]interface{}, dst reflect.Value) error {
+	fields := cachedFields(dst.Type())
+
+	// sorted, so the same document always fails with the same error
+	for _, key := range sortedKeys(src) {
+		f, ok := fields.lookup(key)
+		if !ok {
+			continue
+		}
+
+		value, ok := fieldByIndex(dst, f.index)
+		if !ok {
+			continue
+		}
+
+		var err error
+		if f.quoted {
+			err = dec.decodeQuoted(pathKey(path, key), src[key], value)
+		} else {
+			err = dec.decode(pathKey(path, key), src[key], value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeQuoted decodes values of fields with the string tag option, which
+// hold their json encoded value inside of a string
+func (dec *decoder) decodeQuoted(path string, src interface{}, dst reflect.Value) error {
+	if src == nil {
+		return nil
+	}
+
+	quoted, ok := src.(string)
+	if !ok {
+		return typeError(path, src, dst.Type())
+	}
+
+	inner, err := ParseOptions{Numbers: NumberString}.GogoJson(quoted)
+	if err != nil {
+		return typeError(path, src, dst.Type())
+	}
+	switch inner.(type) {
+	case map[string]interface{}, []interface{}:
+		return typeError(path, src, dst.Type())
+	case string:
+		if dst.Kind() != reflect.String {
+			return typeError(path, src, dst.Type())
+		}
+	}
+
+	return dec.decode(path, inner, dst)
+}
+
+func (dec *decoder) decodeMap(path string, src map[string]interface{}, dst reflect.Value) error {
+	t := dst.Type()
+	if dst.IsNil() {
+		dst.Set(reflect.MakeMapWithSize(t, len(src)))
+	}
+
+	for _, key := range sortedKeys(src) {
+		keyValue := reflect.New(t.Key()).Elem()
+		if err := decodeMapKey(pathKey(path, key), key, keyValue); err != nil {
+			return err
+		}
+
+		value := reflect.New(t.Elem()).Elem()
+		if err := dec.decode(pathKey(path, key), src[key], value); err != nil {
+			return err
+		}
+		dst.SetMapIndex(keyValue, value)
+	}
+
+	return nil
+}
+
+// decodeMapKey converts an object key to the key type of a map, which may
+// implement encoding.TextUnmarshaler
+func decodeMapKey(path string, key string, dst reflect.Value) error {
+	if u, ok := dst.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(key)); err != nil {
+			return &UnmarshalerError{Type: dst.Type(), Path: path, Err: err}
+		}
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(key)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(key, 10, 64)
+		if err != nil || dst.OverflowInt(i) {
+			return typeError(path, key, dst.Type())
+		}
+		dst.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(key, 10, 64)
+		if err != nil || dst.OverflowUint(u) {
+			return typeError(path, key, dst.Type())
+		}
+		dst.SetUint(u)
+	default:
+		return typeError(path, key, dst.Type())
+	}
+
+	return nil
+}
+
+// generic turns the numbers of a value into the configured representation,
+// so it can be stored in an interface
+func (dec *decoder) generic(src interface{}) interface{} {
+	if dec.numbers == NumberString {
+		return src
+	}
+
+	switch value := src.(type) {
+	case Number:
+		converted, _ := numberValue(string(value), dec.numbers)
+		return converted
+	case []interface{}:
+		for i := range value {
+			value[i] = dec.generic(value[i])
+		}
+	case map[string]interface{}:
+		for key := range value {
+			value[key] = dec.generic(value[key])
+		}
+	}
+
+	return src
+}
+
+// fieldByIndex works like reflect.Value.FieldByIndex, but allocates nil
+// pointers to embedded structs on the way. It fails for pointers to
+// unexported embedded structs, which can't be allocated
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func typeError(path string, src interface{}, t reflect.Type) error {
+	return &UnmarshalTypeError{Value: describe(src), Type: t, Path: path}
+}
+
+// describe names the json type of a generic value for error messages
+func describe(src interface{}) string {
+	switch value := src.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	case Number:
+		return "number " + string(value)
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+
+	return reflect.TypeOf(src).String()
+}
+
+var plainKeyRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// pathKey appends an object key to a path, using the bracket notation for
+// keys that aren't plain identifiers
+func pathKey(path string, key string) string {
+	if plainKeyRegex.MatchString(key) {
+		return path + "." + key
+	}
+
+	quoted, _ := Marshal(key)
+	return path + "[" + string(quoted) + "]"
+}
+
+func pathIndex(path string, index int) string {
+	return path + "[" + strconv.Itoa(index) + "]"
+}
@@ -0,0 +1,198 @@
+package gogojson
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Address struct {
+	Street string `json:"street"`
+	City   string
+}
+
+type Timestamps struct {
+	Created int64 `json:"created"`
+	Updated int64 `json:"updated,omitempty"`
+}
+
+type Person struct {
+	*Timestamps
+	Name     string            `json:"name"`
+	Age      int               `json:"age,omitempty"`
+	ID       uint64            `json:"id,string"`
+	Active   bool              `json:"active,string"`
+	Secret   string            `json:"-"`
+	Dash     string            `json:"-,"`
+	Score    float32           `json:"score"`
+	Tags     []string          `json:"tags"`
+	Address  *Address          `json:"address"`
+	Extra    map[string]int    `json:"extra"`
+	ByID     map[int]string    `json:"by_id"`
+	Pair     [2]int            `json:"pair"`
+	Raw      interface{}       `json:"raw"`
+	Exact    Number            `json:"exact"`
+	Blob     []byte            `json:"blob"`
+	Nested   map[string][]bool `json:"nested"`
+	internal string
+}
+
+const personJSON = `{
+  "NAME": "Peter",
+  "age": 42,
+  "id": "18446744073709551615",
+  "active": "true",
+  "Secret": "ignored",
+  "-": "dash",
+  "score": 1.5,
+  "tags": ["dad", "fat"],
+  "address": {"street": "Spooner Street 31", "city": "Quahog"},
+  "extra": {"kids": 3},
+  "by_id": {"1": "Meg", "2": "Chris"},
+  "pair": [1, 2, 3],
+  "raw": {"anything": [1, null]},
+  "exact": 12345678901234567890,
+  "blob": "aGVsbG8=",
+  "nested": {"a": [true, false]},
+  "internal": "ignored",
+  "created": 1577836800,
+  "unknown": {"is": "skipped"}
+}`
+
+func TestUnmarshalStruct(t *testing.T) {
+	assert := assert.New(t)
+	var person Person
+	err := Unmarshal([]byte(personJSON), &person)
+
+	assert.Nil(err)
+	assert.Equal(Person{
+		Timestamps: &Timestamps{Created: 1577836800},
+		Name:       "Peter",
+		Age:        42,
+		ID:         18446744073709551615,
+		Active:     true,
+		Dash:       "dash",
+		Score:      1.5,
+		Tags:       []string{"dad", "fat"},
+		Address:    &Address{Street: "Spooner Street 31", City: "Quahog"},
+		Extra:      map[string]int{"kids": 3},
+		ByID:       map[int]string{1: "Meg", 2: "Chris"},
+		Pair:       [2]int{1, 2},
+		Raw:        map[string]interface{}{"anything": []interface{}{float64(1), nil}},
+		Exact:      Number("12345678901234567890"),
+		Blob:       []byte("hello"),
+		Nested:     map[string][]bool{"a": {true, false}},
+	}, person)
+}
+
+func TestUnmarshalPrimitives(t *testing.T) {
+	assert := assert.New(t)
+
+	var i int8
+	assert.Nil(Unmarshal([]byte(`-128`), &i))
+	assert.Equal(int8(-128), i)
+
+	var s string
+	assert.Nil(Unmarshal([]byte(`"café"`), &s))
+	assert.Equal("café", s)
+
+	var p **float64
+	assert.Nil(Unmarshal([]byte(`0.25`), &p))
+	assert.Equal(0.25, **p)
+
+	assert.Nil(Unmarshal([]byte(`null`), &p))
+	assert.Nil(p)
+
+	// null leaves values that can't be nil alone
+	s = "kept"
+	assert.Nil(Unmarshal([]byte(`null`), &s))
+	assert.Equal("kept", s)
+
+	var generic interface{}
+	assert.Nil(ParseOptions{Numbers: NumberInt64}.Unmarshal([]byte(`[9007199254740993, 0.5]`), &generic))
+	assert.Equal([]interface{}{int64(9007199254740993), 0.5}, generic)
+}
+
+func TestUnmarshalMerge(t *testing.T) {
+	assert := assert.New(t)
+	address := Address{Street: "Old", City: "Quahog"}
+	assert.Nil(Unmarshal([]byte(`{"street": "New"}`), &address))
+	assert.Equal(Address{Street: "New", City: "Quahog"}, address)
+
+	extra := map[string]int{"a": 1}
+	assert.Nil(Unmarshal([]byte(`{"b": 2}`), &extra))
+	assert.Equal(map[string]int{"a": 1, "b": 2}, extra)
+}
+
+func TestUnmarshalEmbeddedConflicts(t *testing.T) {
+	type A struct{ Name, A string }
+	type B struct{ Name, B string }
+	type C struct {
+		Name string `json:"Name"`
+	}
+	type Both struct {
+		A
+		B
+	}
+	type Tagged struct {
+		A
+		C
+	}
+	type Shadowed struct {
+		A
+		Name int
+	}
+	assert := assert.New(t)
+
+	var both Both
+	assert.Nil(Unmarshal([]byte(`{"Name": "x", "A": "a", "B": "b"}`), &both))
+	assert.Equal(Both{A: A{A: "a"}, B: B{B: "b"}}, both)
+
+	var tagged Tagged
+	assert.Nil(Unmarshal([]byte(`{"Name": "x"}`), &tagged))
+	assert.Equal(Tagged{C: C{Name: "x"}}, tagged)
+
+	var shadowed Shadowed
+	assert.Nil(Unmarshal([]byte(`{"Name": 1}`), &shadowed))
+	assert.Equal(Shadowed{Name: 1}, shadowed)
+}
+
+func TestUnmarshalTypeErrors(t *testing.T) {
+	assert := assert.New(t)
+	documents := map[string]*UnmarshalTypeError{
+		`{"name": 1}`:                     {Value: "number 1", Type: reflect.TypeOf(""), Path: "$.name"},
+		`{"age": 1.5}`:                    {Value: "number 1.5", Type: reflect.TypeOf(0), Path: "$.age"},
+		`{"tags": ["a", false]}`:          {Value: "bool", Type: reflect.TypeOf(""), Path: "$.tags[1]"},
+		`{"address": []}`:                 {Value: "array", Type: reflect.TypeOf(Address{}), Path: "$.address"},
+		`{"extra": {"a.b": "x"}}`:         {Value: "string", Type: reflect.TypeOf(0), Path: `$.extra["a.b"]`},
+		`{"by_id": {"one": "Meg"}}`:       {Value: "string", Type: reflect.TypeOf(0), Path: `$.by_id.one`},
+		`{"id": 7}`:                       {Value: "number 7", Type: reflect.TypeOf(uint64(0)), Path: "$.id"},
+		`{"id": "seven"}`:                 {Value: "string", Type: reflect.TypeOf(uint64(0)), Path: "$.id"},
+		`{"score": 1e100}`:                {Value: "number 1e100", Type: reflect.TypeOf(float32(0)), Path: "$.score"},
+		`{"blob": "!!"}`:                  {Value: "string", Type: reflect.TypeOf([]byte{}), Path: "$.blob"},
+		`{"nested": {"x": [true, "no"]}}`: {Value: "string", Type: reflect.TypeOf(false), Path: "$.nested.x[1]"},
+		`[]`:                              {Value: "array", Type: reflect.TypeOf(Person{}), Path: "$"},
+	}
+
+	for source, expected := range documents {
+		var person Person
+		err := Unmarshal([]byte(source), &person)
+
+		assert.Equal(expected, err, source)
+	}
+
+	var small uint8
+	err := Unmarshal([]byte(`256`), &small)
+	assert.Equal("Cannot decode number 256 into go value of type uint8 at $", err.Error())
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	assert := assert.New(t)
+	var person Person
+
+	assert.Equal(&InvalidUnmarshalError{Type: reflect.TypeOf(person)}, Unmarshal([]byte(`{}`), person))
+	assert.Equal(&InvalidUnmarshalError{}, Unmarshal([]byte(`{}`), nil))
+	assert.Equal("Unmarshal needs a non-nil pointer, got gogojson.Person", Unmarshal([]byte(`{}`), person).Error())
+	assert.IsType(&SyntaxError{}, Unmarshal([]byte(`{"name": }`), &person))
+}
@@ -0,0 +1,319 @@
+package gogojson
+
+import (
+	"io"
+)
+
+// lexerSource is a tokenSource that reads tokens lazily from an Iterator
+type lexerSource struct {
+	lexer
+	peeked *Token
+	err    error
+}
+
+func (source *lexerSource) next() (*Token, error) {
+	token, err := source.peek()
+	source.peeked = nil
+
+	return token, err
+}
+
+func (source *lexerSource) peek() (*Token, error) {
+	if source.peeked == nil && source.err == nil {
+		source.peeked, source.err = source.nextToken()
+	}
+
+	return source.peeked, source.err
+}
+
+// Decoder reads a document token by token, without ever holding more than
+// the next token in memory. Besides the raw tokens it can decode or skip whole
+// values, which allows walking huge documents and only materializing the
+// parts that are needed:
+//
+//	dec.Token() // [
+//	for dec.More() {
+//		value, err := dec.Decode()
+//	}
+//	dec.Token() // ]
+//
+// Commas and colons are checked no matter which methods are used, so a
+// missing, leading, repeated or trailing comma is a *SyntaxError
+type Decoder struct {
+	source *lexerSource
+	parser *parser
+	// the arrays and objects that are currently open
+	nesting nesting
+}
+
+// NewDecoder returns a Decoder reading from source
+func NewDecoder(source Iterator) *Decoder {
+	return ParseOptions{}.NewDecoder(source)
+}
+
+// NewDecoder works like the package level NewDecoder, but Decode applies the
+// options
+func (opts ParseOptions) NewDecoder(source Iterator) *Decoder {
+	tokens := &lexerSource{lexer: lexer{source}}
+
+	return &Decoder{
+		source: tokens,
+		parser: newParser(opts, tokens),
+	}
+}
+
+// Token consumes and returns the next token, including punctuation. io.EOF
+// is returned at the end of input
+func (dec *Decoder) Token() (Token, error) {
+	token, err := dec.Peek()
+	if err == io.EOF && len(dec.nesting) > 0 {
+		return Token{}, newSyntaxError(dec.parser.last, "Unexpected end of input")
+	} else if err != nil {
+		return Token{}, err
+	}
+
+	if err := dec.nesting.check(&token); err != nil {
+		return Token{}, err
+	}
+	dec.parser.shift()
+	dec.nesting.advance(&token)
+
+	return token, nil
+}
+
+// Peek returns the next token without consuming it
+func (dec *Decoder) Peek() (Token, error) {
+	token, err := dec.source.peek()
+	if err != nil {
+		return Token{}, err
+	} else if token == nil {
+		return Token{}, io.EOF
+	}
+
+	return *token, nil
+}
+
+// More reports whether another element follows in the current array or
+// object. The comma in front of that element is consumed, so Token returns
+// the element itself
+func (dec *Decoder) More() bool {
+	if dec.skipComma() != nil {
+		return false
+	}
+	token, err := dec.source.peek()
+
+	return err == nil && token != nil && !(token.Type == PUNC && (token.Value == "]" || token.Value == "}"))
+}
+
+// Key reads the key of the next object entry, including the colon after it
+// and the comma before it, if any
+func (dec *Decoder) Key() (string, error) {
+	if err := dec.skipComma(); err != nil {
+		return "", err
+	}
+
+	token, err := dec.Peek()
+	if err != nil {
+		// Token reports the end of input inside of the object
+		_, err = dec.Token()
+		return "", err
+	}
+	if err := dec.nesting.check(&token); err != nil {
+		return "", err
+	} else if !dec.nesting.expectsKey() {
+		return "", newSyntaxError(token.Position, "Unexpected token '%v', expected a key", token.Value)
+	}
+	key, err := dec.Token()
+	if err != nil {
+		return "", err
+	}
+	if _, err := dec.Token(); err != nil {
+		return "", err
+	}
+
+	return key.Value.(string), nil
+}
+
+// Decode reads the next value, skipping the comma before it if there is one
+func (dec *Decoder) Decode() (value interface{}, err error) {
+	err = dec.readValue(func() error {
+		value, err = dec.parser.parseValue()
+		return err
+	})
+
+	return value, err
+}
+
+// Skip consumes the next value, like Decode, but without building it
+func (dec *Decoder) Skip() error {
+	return dec.readValue(dec.skipValue)
+}
+
+// readValue checks that a value may follow and lets read consume it
+func (dec *Decoder) readValue(read func() error) error {
+	if err := dec.skipComma(); err != nil {
+		return err
+	}
+
+	token, err := dec.source.peek()
+	if err != nil {
+		return err
+	} else if token == nil {
+		// reports the end of input
+		return read()
+	}
+	if err := dec.nesting.check(token); err != nil {
+		return err
+	}
+
+	first := *token
+	if err := read(); err != nil {
+		return err
+	}
+	if first.Type == PUNC {
+		dec.nesting.valueDone()
+	} else {
+		// a string may as well have been a key
+		dec.nesting.advance(&first)
+	}
+
+	return nil
+}
+
+// skipValue consumes the next value without building it, checking brackets,
+// commas and colons on the way
+func (dec *Decoder) skipValue() error {
+	var inner nesting
+	for {
+		token, err := dec.parser.shift()
+		if err != nil {
+			return err
+		}
+		if err := inner.check(token); err != nil {
+			return err
+		}
+		inner.advance(token)
+
+		if len(inner) == 0 {
+			return nil
+		}
+	}
+}
+
+// skipComma consumes the comma after an element of the current array or
+// object
+func (dec *Decoder) skipComma() error {
+	if top := dec.nesting.top(); top == nil || top.state != expectComma {
+		return nil
+	}
+
+	token, err := dec.source.peek()
+	if err != nil || token == nil || token.Type != PUNC || token.Value != "," {
+		return err
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// container is an open array or object, with what has to come next in it
+type container struct {
+	closing string
+	state   int
+}
+
+const (
+	// an element or the closing bracket, right after the opening one
+	expectFirst = iota
+	// a comma or the closing bracket, after an element
+	expectComma
+	// an element, after a comma
+	expectElement
+	// the colon after a key
+	expectColon
+	// the value of an object entry, after the colon
+	expectValue
+)
+
+// nesting checks the order of tokens in the arrays and objects it tracks.
+// Outside of any container only values are allowed, any number of them
+type nesting []container
+
+func (n nesting) top() *container {
+	if len(n) == 0 {
+		return nil
+	}
+
+	return &n[len(n)-1]
+}
+
+// expectsKey reports whether the next token is the key of an object entry
+func (n nesting) expectsKey() bool {
+	top := n.top()
+	return top != nil && top.closing == "}" && (top.state == expectFirst || top.state == expectElement)
+}
+
+// check reports a *SyntaxError if token can't come next
+func (n nesting) check(token *Token) error {
+	top := n.top()
+	isPunc := func(values ...string) bool {
+		for _, value := range values {
+			if token.Type == PUNC && token.Value == value {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case top != nil && top.state == expectFirst && isPunc(top.closing):
+		return nil
+	case top != nil && top.state == expectComma:
+		if isPunc(",", top.closing) {
+			return nil
+		}
+		return newSyntaxError(token.Position, "Expected punctuation with value ',', instead got: '%v'", token.Value)
+	case top != nil && top.state == expectColon:
+		if isPunc(":") {
+			return nil
+		}
+		return newSyntaxError(token.Position, "Expected punctuation with value ':', instead got: '%v'", token.Value)
+	case n.expectsKey():
+		if token.Type == STRING {
+			return nil
+		}
+		return newSyntaxError(token.Position, "Expected string key, instead got a %s", token.Type)
+	case isPunc(",", ":", "]", "}"):
+		return newSyntaxError(token.Position, "Unexpected token '%v'", token.Value)
+	}
+
+	return nil
+}
+
+// advance updates the state for a token that passed check
+func (n *nesting) advance(token *Token) {
+	top := n.top()
+	switch {
+	case token.Type == PUNC && token.Value == ",":
+		top.state = expectElement
+	case token.Type == PUNC && token.Value == ":":
+		top.state = expectValue
+	case token.Type == PUNC && (token.Value == "]" || token.Value == "}"):
+		*n = (*n)[:len(*n)-1]
+		n.valueDone()
+	case token.Type == PUNC && token.Value == "[":
+		*n = append(*n, container{closing: "]"})
+	case token.Type == PUNC && token.Value == "{":
+		*n = append(*n, container{closing: "}"})
+	case n.expectsKey():
+		top.state = expectColon
+	default:
+		n.valueDone()
+	}
+}
+
+// valueDone marks the current element as complete
+func (n nesting) valueDone() {
+	if top := n.top(); top != nil {
+		top.state = expectComma
+	}
+}
@@ -0,0 +1,218 @@
+package gogojson
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecoderToken(t *testing.T) {
+	assert := assert.New(t)
+	expected, err := Tokenize(MakeIterator(tokenizeJSON))
+	assert.Nil(err)
+
+	dec := NewDecoder(MakeIterator(tokenizeJSON))
+	for _, token := range expected {
+		peeked, err := dec.Peek()
+		assert.Nil(err)
+		assert.Equal(*token, peeked)
+
+		next, err := dec.Token()
+		assert.Nil(err)
+		assert.Equal(*token, next)
+	}
+
+	_, err = dec.Token()
+	assert.Equal(io.EOF, err)
+	_, err = dec.Peek()
+	assert.Equal(io.EOF, err)
+	assert.False(dec.More())
+}
+
+func TestDecoderWalk(t *testing.T) {
+	assert := assert.New(t)
+	dec := ParseOptions{Numbers: NumberInt64}.NewDecoder(MakeReaderIterator(strings.NewReader(`[
+	  {"id": 1, "name": "Peter", "kids": [{"name": "Meg"}, {"name": "Chris"}]},
+	  {"id": 2, "name": "Lois", "kids": []}
+	]`)))
+
+	token, err := dec.Token()
+	assert.Nil(err)
+	assert.Equal("[", token.Value)
+
+	names := []interface{}{}
+	for dec.More() {
+		_, err := dec.Token()
+		assert.Nil(err)
+
+		for dec.More() {
+			key, err := dec.Key()
+			assert.Nil(err)
+
+			if key == "name" {
+				name, err := dec.Decode()
+				assert.Nil(err)
+				names = append(names, name)
+			} else if key == "id" {
+				id, err := dec.Decode()
+				assert.Nil(err)
+				assert.IsType(int64(0), id)
+			} else {
+				assert.Nil(dec.Skip())
+			}
+		}
+
+		token, err = dec.Token()
+		assert.Nil(err)
+		assert.Equal("}", token.Value)
+	}
+
+	token, err = dec.Token()
+	assert.Nil(err)
+	assert.Equal("]", token.Value)
+	assert.Equal([]interface{}{"Peter", "Lois"}, names)
+
+	_, err = dec.Token()
+	assert.Equal(io.EOF, err)
+}
+
+func TestDecoderDecodeSequence(t *testing.T) {
+	assert := assert.New(t)
+	dec := NewDecoder(MakeIterator(`{"a": 1} [2] "three"`))
+
+	values := []interface{}{}
+	for dec.More() {
+		value, err := dec.Decode()
+		assert.Nil(err)
+		values = append(values, value)
+	}
+
+	assert.Equal([]interface{}{map[string]interface{}{"a": float64(1)}, []interface{}{float64(2)}, "three"}, values)
+}
+
+func TestDecoderErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	dec := NewDecoder(MakeIterator(`[1, !]`))
+	dec.Token()
+	_, err := dec.Decode()
+	assert.Nil(err)
+	_, err = dec.Decode()
+	assert.IsType(&SyntaxError{}, err)
+	assert.False(dec.More())
+	_, err = dec.Token()
+	assert.IsType(&SyntaxError{}, err)
+
+	dec = NewDecoder(MakeIterator(`{"a" 1}`))
+	dec.Token()
+	_, err = dec.Key()
+	assert.Equal(&SyntaxError{
+		Msg:      "Expected punctuation with value ':', instead got: '1'",
+		Position: Position{Line: 1, Column: 6, Offset: 5},
+	}, err)
+
+	dec = NewDecoder(MakeIterator(`{"a": [1, [2`))
+	dec.Token()
+	dec.Key()
+	assert.Equal(&SyntaxError{
+		Msg:      "Unexpected end of input",
+		Position: Position{Line: 1, Column: 12, Offset: 11},
+	}, dec.Skip())
+
+	dec = NewDecoder(MakeIterator(`]`))
+	assert.Equal(&SyntaxError{
+		Msg:      "Unexpected token ']'",
+		Position: Position{Line: 1, Column: 1, Offset: 0},
+	}, dec.Skip())
+}
+
+func TestDecoderSeparators(t *testing.T) {
+	assert := assert.New(t)
+
+	decodeAll := func(source string) error {
+		dec := NewDecoder(MakeIterator(source))
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+		for dec.More() {
+			if _, err := dec.Decode(); err != nil {
+				return err
+			}
+		}
+		_, err := dec.Token()
+		return err
+	}
+
+	cases := map[string]*SyntaxError{
+		`[1 2 3]`:  {Msg: "Expected punctuation with value ',', instead got: '2'", Position: Position{Line: 1, Column: 4, Offset: 3}},
+		`[,,1]`:    {Msg: "Unexpected token ','", Position: Position{Line: 1, Column: 2, Offset: 1}},
+		`[1,,2]`:   {Msg: "Unexpected token ','", Position: Position{Line: 1, Column: 4, Offset: 3}},
+		`[1, 2,]`:  {Msg: "Unexpected token ']'", Position: Position{Line: 1, Column: 7, Offset: 6}},
+		`[1, 2}`:   {Msg: "Expected punctuation with value ',', instead got: '}'", Position: Position{Line: 1, Column: 6, Offset: 5}},
+		`[1, [2}]`: {Msg: "Expected punctuation with value ',', instead got: '}'", Position: Position{Line: 1, Column: 7, Offset: 6}},
+		`[1, 2`:    {Msg: "Unexpected end of input", Position: Position{Line: 1, Column: 5, Offset: 4}},
+	}
+	for source, expected := range cases {
+		assert.Equal(expected, decodeAll(source), source)
+	}
+	assert.Nil(decodeAll(`[1, [2, 3], {"a": [4]}]`))
+
+	keys := map[string]*SyntaxError{
+		`{"a": 1 "b": 2}`:   {Msg: "Expected punctuation with value ',', instead got: 'b'", Position: Position{Line: 1, Column: 9, Offset: 8}},
+		`{"a": 1,}`:         {Msg: "Expected string key, instead got a PUNC", Position: Position{Line: 1, Column: 9, Offset: 8}},
+		`{,"a": 1}`:         {Msg: "Expected string key, instead got a PUNC", Position: Position{Line: 1, Column: 2, Offset: 1}},
+		`{"a": 1,, "b": 2}`: {Msg: "Expected string key, instead got a PUNC", Position: Position{Line: 1, Column: 9, Offset: 8}},
+	}
+	for source, expected := range keys {
+		dec := NewDecoder(MakeIterator(source))
+		dec.Token()
+		var err error
+		for err == nil && dec.More() {
+			if _, err = dec.Key(); err == nil {
+				err = dec.Skip()
+			}
+		}
+		if err == nil {
+			_, err = dec.Token()
+		}
+		assert.Equal(expected, err, source)
+	}
+
+	dec := NewDecoder(MakeIterator(`{"a": 1,`))
+	dec.Token()
+	dec.Key()
+	dec.Decode()
+	_, err := dec.Key()
+	assert.EqualError(err, "Unexpected end of input (line 1, column 8, offset 7)")
+
+	dec = NewDecoder(MakeIterator(`[1]`))
+	_, err = dec.Key()
+	assert.EqualError(err, "Unexpected token '[', expected a key (line 1, column 1, offset 0)")
+}
+
+func TestDecoderSkipErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := map[string]string{
+		`[1}`:         "Expected punctuation with value ',', instead got: '}' (line 1, column 3, offset 2)",
+		`[{"a":1}}`:   "Expected punctuation with value ',', instead got: '}' (line 1, column 9, offset 8)",
+		`[{"a": 1]}`:  "Expected punctuation with value ',', instead got: ']' (line 1, column 9, offset 8)",
+		`[1 2]`:       "Expected punctuation with value ',', instead got: '2' (line 1, column 4, offset 3)",
+		`[1,]`:        "Unexpected token ']' (line 1, column 4, offset 3)",
+		`{"a" 1}`:     "Expected punctuation with value ':', instead got: '1' (line 1, column 6, offset 5)",
+		`{"a": 1, 2}`: "Expected string key, instead got a NUM (line 1, column 10, offset 9)",
+		`,`:           "Unexpected token ',' (line 1, column 1, offset 0)",
+		`:`:           "Unexpected token ':' (line 1, column 1, offset 0)",
+		`[:]`:         "Unexpected token ':' (line 1, column 2, offset 1)",
+		`{"a": ,}`:    "Unexpected token ',' (line 1, column 7, offset 6)",
+	}
+	for source, expected := range cases {
+		assert.EqualError(NewDecoder(MakeIterator(source)).Skip(), expected, source)
+	}
+
+	dec := NewDecoder(MakeIterator(`[{"a": [1, {}]}, "b"] 2`))
+	assert.Nil(dec.Skip())
+	assert.Nil(dec.Skip())
+}
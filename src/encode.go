@@ -0,0 +1,391 @@
+package gogojson
+
+import (
+	"encoding"
+	"encoding/base64"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// encoderFunc writes a value of the type it was built for
+type encoderFunc func(enc *encoder, v reflect.Value) error
+
+// maxNesting is how deep pointers, maps and slices may be nested before the
+// value is assumed to contain a cycle
+const maxNesting = 1000
+
+var (
+	bigIntType        = reflect.TypeOf(&big.Int{})
+	bigFloatType      = reflect.TypeOf(&big.Float{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+var encoderCache sync.Map
+
+// typeEncoder returns the encoder for t, building it only once per type
+func typeEncoder(t reflect.Type) encoderFunc {
+	if f, ok := encoderCache.Load(t); ok {
+		return f.(encoderFunc)
+	}
+
+	// recursive types find this placeholder while their encoder is built
+	var wg sync.WaitGroup
+	var built encoderFunc
+	wg.Add(1)
+	f, loaded := encoderCache.LoadOrStore(t, encoderFunc(func(enc *encoder, v reflect.Value) error {
+		wg.Wait()
+		return built(enc, v)
+	}))
+	if loaded {
+		return f.(encoderFunc)
+	}
+
+	built = newTypeEncoder(t)
+	wg.Done()
+	encoderCache.Store(t, built)
+
+	return built
+}
+
+func newTypeEncoder(t reflect.Type) encoderFunc {
+	switch t {
+	case numberType, bigIntType, bigFloatType:
+		return interfaceValueEncoder
+	}
+	if f := newMarshalerEncoder(t); f != nil {
+		return f
+	}
+
+	return newKindEncoder(t)
+}
+
+// newKindEncoder builds the encoder for a type by its kind, ignoring any
+// marshaling methods of the type itself
+func newKindEncoder(t reflect.Type) encoderFunc {
+	switch t.Kind() {
+	case reflect.Bool:
+		return boolEncoder
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return intEncoder
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return uintEncoder
+	case reflect.Float32, reflect.Float64:
+		return floatEncoder
+	case reflect.String:
+		return stringEncoder
+	case reflect.Interface:
+		return interfaceEncoder
+	case reflect.Struct:
+		return newStructEncoder(t)
+	case reflect.Map:
+		return newMapEncoder(t)
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return bytesEncoder
+		}
+		return newSliceEncoder(t)
+	case reflect.Array:
+		return newArrayEncoder(t)
+	case reflect.Ptr:
+		return newPtrEncoder(t)
+	}
+
+	return unsupportedTypeEncoder
+}
+
+// interfaceValueEncoder hands values back to the generic encoder, which knows
+// Number and the math/big types
+func interfaceValueEncoder(enc *encoder, v reflect.Value) error {
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		enc.buf.WriteString("null")
+		return nil
+	}
+
+	return enc.encode(v.Interface())
+}
+
+func boolEncoder(enc *encoder, v reflect.Value) error {
+	enc.buf.WriteString(strconv.FormatBool(v.Bool()))
+	return nil
+}
+
+func intEncoder(enc *encoder, v reflect.Value) error {
+	enc.buf.WriteString(strconv.FormatInt(v.Int(), 10))
+	return nil
+}
+
+func uintEncoder(enc *encoder, v reflect.Value) error {
+	enc.buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+	return nil
+}
+
+func floatEncoder(enc *encoder, v reflect.Value) error {
+	return enc.encodeFloat(v.Float(), v.Type().Bits())
+}
+
+func stringEncoder(enc *encoder, v reflect.Value) error {
+	enc.encodeString(v.String())
+	return nil
+}
+
+func interfaceEncoder(enc *encoder, v reflect.Value) error {
+	if v.IsNil() {
+		enc.buf.WriteString("null")
+		return nil
+	}
+
+	return enc.encode(v.Elem().Interface())
+}
+
+func bytesEncoder(enc *encoder, v reflect.Value) error {
+	if v.IsNil() {
+		enc.buf.WriteString("null")
+		return nil
+	}
+
+	enc.buf.WriteByte('"')
+	enc.buf.WriteString(base64.StdEncoding.EncodeToString(v.Bytes()))
+	enc.buf.WriteByte('"')
+	return nil
+}
+
+func unsupportedTypeEncoder(enc *encoder, v reflect.Value) error {
+	return &UnsupportedTypeError{Type: v.Type().String()}
+}
+
+func newStructEncoder(t reflect.Type) encoderFunc {
+	fields := cachedFields(t)
+	encoders := make([]encoderFunc, len(fields.list))
+	for i, f := range fields.list {
+		encoders[i] = typeEncoder(f.typ)
+	}
+
+	return func(enc *encoder, v reflect.Value) error {
+		// the object is only indented once a field is written, omitempty may
+		// leave it without any
+		enc.open('{', 0)
+		written := 0
+		for i := range fields.list {
+			f := &fields.list[i]
+			value, ok := fieldValue(v, f.index)
+			if !ok || (f.omitEmpty && isEmptyValue(value)) {
+				continue
+			}
+
+			if written == 0 {
+				enc.depth++
+			}
+			enc.separate(written)
+			written++
+			enc.encodeString(f.name)
+			enc.colon()
+
+			var err error
+			if f.quoted {
+				err = enc.encodeQuoted(value, encoders[i])
+			} else {
+				err = encoders[i](enc, value)
+			}
+			if err != nil {
+				return atPath(err, pathKey("", f.name))
+			}
+		}
+		enc.close('}', written)
+
+		return nil
+	}
+}
+
+// encodeQuoted writes a value as a string containing its json, which is what
+// the string tag option asks for
+func (enc *encoder) encodeQuoted(v reflect.Value, f encoderFunc) error {
+	inner := &encoder{}
+	if err := f(inner, v); err != nil {
+		return err
+	}
+
+	enc.encodeString(inner.buf.String())
+	return nil
+}
+
+// fieldValue works like reflect.Value.FieldByIndex, but reports false
+// instead of panicking at nil pointers to embedded structs
+func fieldValue(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+func newMapEncoder(t reflect.Type) encoderFunc {
+	keyType := t.Key()
+	if keyType.Kind() != reflect.String && !keyType.Implements(textMarshalerType) {
+		switch keyType.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			return unsupportedTypeEncoder
+		}
+	}
+	elemEncoder := typeEncoder(t.Elem())
+
+	return func(enc *encoder, v reflect.Value) error {
+		if v.IsNil() {
+			enc.buf.WriteString("null")
+			return nil
+		}
+		if err := enc.enter(); err != nil {
+			return err
+		}
+		defer enc.leave()
+
+		type entry struct {
+			key   string
+			value reflect.Value
+		}
+		entries := make([]entry, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := mapKey(iter.Key())
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry{key: key, value: iter.Value()})
+		}
+		if enc.opts.SortKeys {
+			sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+		}
+
+		enc.open('{', len(entries))
+		for i, e := range entries {
+			enc.separate(i)
+			enc.encodeString(e.key)
+			enc.colon()
+			if err := elemEncoder(enc, e.value); err != nil {
+				return atPath(err, pathKey("", e.key))
+			}
+		}
+		enc.close('}', len(entries))
+
+		return nil
+	}
+}
+
+// mapKey turns a map key into the string used as object key
+func mapKey(key reflect.Value) (string, error) {
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+
+	if key.Type().Implements(textMarshalerType) {
+		if key.Kind() == reflect.Ptr && key.IsNil() {
+			return "", nil
+		}
+		text, err := key.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return "", &MarshalerError{Type: key.Type(), Err: err}
+		}
+		return string(text), nil
+	}
+
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	}
+
+	return strconv.FormatUint(key.Uint(), 10), nil
+}
+
+func newSliceEncoder(t reflect.Type) encoderFunc {
+	arrayEncoder := newArrayEncoder(t)
+
+	return func(enc *encoder, v reflect.Value) error {
+		if v.IsNil() {
+			enc.buf.WriteString("null")
+			return nil
+		}
+		if err := enc.enter(); err != nil {
+			return err
+		}
+		defer enc.leave()
+
+		return arrayEncoder(enc, v)
+	}
+}
+
+func newArrayEncoder(t reflect.Type) encoderFunc {
+	elemEncoder := typeEncoder(t.Elem())
+
+	return func(enc *encoder, v reflect.Value) error {
+		enc.open('[', v.Len())
+		for i := 0; i < v.Len(); i++ {
+			enc.separate(i)
+			if err := elemEncoder(enc, v.Index(i)); err != nil {
+				return atPath(err, pathIndex("", i))
+			}
+		}
+		enc.close(']', v.Len())
+
+		return nil
+	}
+}
+
+func newPtrEncoder(t reflect.Type) encoderFunc {
+	elemEncoder := typeEncoder(t.Elem())
+
+	return func(enc *encoder, v reflect.Value) error {
+		if v.IsNil() {
+			enc.buf.WriteString("null")
+			return nil
+		}
+		if err := enc.enter(); err != nil {
+			return err
+		}
+		defer enc.leave()
+
+		return elemEncoder(enc, v.Elem())
+	}
+}
+
+// enter and leave track the nesting of values that may form cycles
+func (enc *encoder) enter() error {
+	enc.nesting++
+	if enc.nesting > maxNesting {
+		return &UnsupportedValueError{Value: "value nested too deeply, it probably contains a cycle"}
+	}
+
+	return nil
+}
+
+func (enc *encoder) leave() {
+	enc.nesting--
+}
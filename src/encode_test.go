@@ -0,0 +1,167 @@
+package gogojson
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type upperKey struct {
+	name string
+}
+
+func (k upperKey) MarshalText() ([]byte, error) {
+	if k.name == "" {
+		return nil, errors.New("empty key")
+	}
+	return []byte(strings.ToUpper(k.name)), nil
+}
+
+type node struct {
+	Value int   `json:"value"`
+	Next  *node `json:"next,omitempty"`
+}
+
+func TestMarshalStruct(t *testing.T) {
+	assert := assert.New(t)
+
+	person := Person{
+		Timestamps: &Timestamps{Created: 1577836800},
+		Name:       "Peter",
+		ID:         7,
+		Active:     true,
+		Secret:     "hidden",
+		Dash:       "dash",
+		Score:      1.5,
+		Tags:       []string{"dad"},
+		Address:    &Address{Street: "Spooner Street 31", City: "Quahog"},
+		ByID:       map[int]string{2: "Chris", 1: "Meg"},
+		Pair:       [2]int{1, 2},
+		Raw:        map[string]interface{}{"a": []interface{}{1.0, nil}},
+		Exact:      Number("12345678901234567890"),
+		Blob:       []byte("hello"),
+		internal:   "ignored",
+	}
+
+	out, err := MarshalOptions{SortKeys: true}.Marshal(person)
+	assert.Nil(err)
+	assert.Equal(`{"created":1577836800,"name":"Peter","id":"7","active":"true","-":"dash",`+
+		`"score":1.5,"tags":["dad"],"address":{"street":"Spooner Street 31","City":"Quahog"},`+
+		`"extra":null,"by_id":{"1":"Meg","2":"Chris"},"pair":[1,2],"raw":{"a":[1,null]},`+
+		`"exact":12345678901234567890,"blob":"aGVsbG8=","nested":null}`, string(out))
+
+	var decoded Person
+	assert.Nil(Unmarshal(out, &decoded))
+	person.Secret, person.internal = "", ""
+	assert.Equal(person, decoded)
+
+	// a nil embedded pointer hides its fields
+	out, err = Marshal(&Person{Name: "Meg"})
+	assert.Nil(err)
+	assert.False(strings.Contains(string(out), "created"))
+	assert.True(strings.HasPrefix(string(out), `{"name":"Meg","id":"0"`))
+}
+
+func TestMarshalIndentOmittedFields(t *testing.T) {
+	assert := assert.New(t)
+
+	type empty struct {
+		A string `json:"a,omitempty"`
+		B []int  `json:"b,omitempty"`
+	}
+	type outer struct {
+		First empty `json:"first"`
+		Items []int `json:"items"`
+		Last  empty `json:"last"`
+	}
+
+	out, err := MarshalIndent(outer{Items: []int{1}}, "", "  ")
+	assert.Nil(err)
+	assert.Equal("{\n  \"first\": {},\n  \"items\": [\n    1\n  ],\n  \"last\": {}\n}", string(out))
+}
+
+func TestMarshalReflectValues(t *testing.T) {
+	assert := assert.New(t)
+
+	two := 2
+	var nilMap map[string]int
+	var nilPtr *Address
+
+	cases := []struct {
+		value    interface{}
+		expected string
+	}{
+		{[]int{1, 2, 3}, `[1,2,3]`},
+		{[]string(nil), `null`},
+		{[0]int{}, `[]`},
+		{[]*int{&two, nil}, `[2,null]`},
+		{map[string]int{"a": 1}, `{"a":1}`},
+		{nilMap, `null`},
+		{nilPtr, `null`},
+		{map[upperKey]bool{{"yes"}: true}, `{"YES":true}`},
+		{map[uint8][]float32{7: {0.5}}, `{"7":[0.5]}`},
+		{[]interface{}{struct{}{}, []byte{}}, `[{},""]`},
+		{[]*big.Int{big.NewInt(-3), nil}, `[-3,null]`},
+		{struct{ A, b int }{1, 2}, `{"A":1}`},
+	}
+	for _, c := range cases {
+		out, err := Marshal(c.value)
+		assert.Nil(err, c.expected)
+		assert.Equal(c.expected, string(out))
+	}
+
+	out, err := MarshalIndent(struct {
+		A []int
+		B struct{} `json:"b"`
+	}{A: []int{1}}, "", "  ")
+	assert.Nil(err)
+	assert.Equal("{\n  \"A\": [\n    1\n  ],\n  \"b\": {}\n}", string(out))
+}
+
+func TestMarshalReflectErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := Marshal(map[[2]int]string{{1, 2}: "a"})
+	assert.Equal(&UnsupportedTypeError{Type: "map[[2]int]string"}, err)
+
+	_, err = Marshal(struct{ F func() }{})
+	assert.Equal(&UnsupportedTypeError{Type: "func()"}, err)
+
+	_, err = Marshal(map[string]interface{}{"keys": map[upperKey]int{{}: 1}})
+	assert.EqualError(err, "Cannot encode go value of type gogojson.upperKey at $.keys: empty key")
+
+	_, err = Marshal([]float64{1, -1 / zero()})
+	assert.Equal(&UnsupportedValueError{Value: "-Inf"}, err)
+
+	cycle := &node{Value: 1}
+	cycle.Next = cycle
+	_, err = Marshal(cycle)
+	assert.IsType(&UnsupportedValueError{}, err)
+
+	generic := map[string]interface{}{}
+	generic["self"] = generic
+	_, err = Marshal(generic)
+	assert.IsType(&UnsupportedValueError{}, err)
+}
+
+func TestMarshalRecursiveType(t *testing.T) {
+	assert := assert.New(t)
+
+	list := &node{Value: 1, Next: &node{Value: 2, Next: &node{Value: 3}}}
+	out, err := Marshal(list)
+	assert.Nil(err)
+	assert.Equal(`{"value":1,"next":{"value":2,"next":{"value":3}}}`, string(out))
+
+	// the encoder is built once and shared afterwards
+	first := typeEncoder(reflect.TypeOf(list))
+	second := typeEncoder(reflect.TypeOf(list))
+	assert.Equal(reflect.ValueOf(first).Pointer(), reflect.ValueOf(second).Pointer())
+}
+
+func zero() float64 {
+	return 0
+}
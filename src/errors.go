@@ -0,0 +1,172 @@
+package gogojson
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Position describes where in the input a token starts or an error happened.
+// Line and Column are 1-based, Offset is the 0-based byte offset
+type Position struct {
+	Line   uint64
+	Column uint64
+	Offset uint64
+}
+
+// SyntaxError is returned whenever the input is not valid json
+type SyntaxError struct {
+	Msg string
+	Position
+	// Pointer refers to the innermost value that was being parsed, it is
+	// empty for errors outside of any object or array
+	Pointer Pointer
+}
+
+func (err *SyntaxError) Error() string {
+	return fmt.Sprintf("%s (line %d, column %d, offset %d)", err.Msg, err.Line, err.Column, err.Offset)
+}
+
+func newSyntaxError(pos Position, format string, args ...interface{}) *SyntaxError {
+	return &SyntaxError{
+		Msg:      fmt.Sprintf(format, args...),
+		Position: pos,
+	}
+}
+
+// UnsupportedTypeError is returned by Marshal for values that have no json
+// representation
+type UnsupportedTypeError struct {
+	Type string
+}
+
+func (err *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("Unsupported type: %s", err.Type)
+}
+
+// UnsupportedValueError is returned by Marshal for values of a supported type
+// that still can't be represented, like NaN
+type UnsupportedValueError struct {
+	Value string
+}
+
+func (err *UnsupportedValueError) Error() string {
+	return fmt.Sprintf("Unsupported value: %s", err.Value)
+}
+
+// RecordError is returned by NDJSONReader for a record that couldn't be read
+type RecordError struct {
+	// Record is the 0-based index of the record, not counting blank lines
+	Record int
+	// Line is the line the record is on
+	Line uint64
+	Err  error
+}
+
+func (err *RecordError) Error() string {
+	return fmt.Sprintf("record %d (line %d): %s", err.Record, err.Line, err.Err)
+}
+
+func (err *RecordError) Unwrap() error {
+	return err.Err
+}
+
+// InvalidUnmarshalError is returned by Unmarshal when it isn't given a
+// non-nil pointer to decode into
+type InvalidUnmarshalError struct {
+	Type reflect.Type
+}
+
+func (err *InvalidUnmarshalError) Error() string {
+	if err.Type == nil {
+		return "Unmarshal needs a non-nil pointer, got nil"
+	}
+
+	return fmt.Sprintf("Unmarshal needs a non-nil pointer, got %s", err.Type)
+}
+
+// UnmarshalTypeError is returned by Unmarshal for a json value that doesn't
+// fit the go type it should be decoded into
+type UnmarshalTypeError struct {
+	// Value describes the json value, like "string" or "number 1.5"
+	Value string
+	Type  reflect.Type
+	// Path locates the value in the document, like $.items[3].name
+	Path string
+}
+
+func (err *UnmarshalTypeError) Error() string {
+	return fmt.Sprintf("Cannot decode %s into go value of type %s at %s", err.Value, err.Type, err.Path)
+}
+
+// MarshalerError is returned by Marshal when a Marshaler or
+// encoding.TextMarshaler fails or produces invalid json
+type MarshalerError struct {
+	Type reflect.Type
+	// Path locates the value in the encoded document, like $.items[3].name
+	Path string
+	Err  error
+}
+
+func (err *MarshalerError) Error() string {
+	return fmt.Sprintf("Cannot encode go value of type %s at %s: %s", err.Type, err.Path, err.Err)
+}
+
+func (err *MarshalerError) Unwrap() error {
+	return err.Err
+}
+
+// UnmarshalerError is returned by Unmarshal when an Unmarshaler or
+// encoding.TextUnmarshaler fails
+type UnmarshalerError struct {
+	Type reflect.Type
+	// Path locates the value in the document, like $.items[3].name
+	Path string
+	Err  error
+}
+
+func (err *UnmarshalerError) Error() string {
+	return fmt.Sprintf("Cannot decode into go value of type %s at %s: %s", err.Type, err.Path, err.Err)
+}
+
+func (err *UnmarshalerError) Unwrap() error {
+	return err.Err
+}
+
+// PathSyntaxError is returned by Compile for malformed JSONPath expressions
+type PathSyntaxError struct {
+	Msg string
+	// Column is the 1-based column of the expression the error was found at
+	Column int
+}
+
+func (err *PathSyntaxError) Error() string {
+	return fmt.Sprintf("%s (column %d)", err.Msg, err.Column)
+}
+
+// PointerError is returned for JSON pointers that are malformed or don't
+// fit the tree they are applied to
+type PointerError struct {
+	Msg     string
+	Pointer string
+}
+
+func (err *PointerError) Error() string {
+	return fmt.Sprintf("%s (pointer %q)", err.Msg, err.Pointer)
+}
+
+// PatchError is returned for a JSON Patch operation that is malformed or
+// can't be applied
+type PatchError struct {
+	// Index is the 0-based index of the operation in the patch
+	Index int
+	Op    string
+	Err   error
+}
+
+func (err *PatchError) Error() string {
+	return fmt.Sprintf("patch operation %d (%s): %s", err.Index, err.Op, err.Err)
+}
+
+func (err *PatchError) Unwrap() error {
+	return err.Err
+}
@@ -0,0 +1,247 @@
+package gogojson
+
+import (
+	"sort"
+)
+
+// extractNode is a trie of the steps of all paths to extract
+type extractNode struct {
+	keys    map[string]*extractNode
+	indices map[int]*extractNode
+	// paths lists the paths that end at this node
+	paths []string
+}
+
+func (node *extractNode) add(steps []queryStep, path string) {
+	for _, step := range steps {
+		var next *extractNode
+		if step.isIndex {
+			if node.indices == nil {
+				node.indices = map[int]*extractNode{}
+			}
+			if next = node.indices[step.index]; next == nil {
+				next = &extractNode{}
+				node.indices[step.index] = next
+			}
+		} else {
+			if node.keys == nil {
+				node.keys = map[string]*extractNode{}
+			}
+			if next = node.keys[step.key]; next == nil {
+				next = &extractNode{}
+				node.keys[step.key] = next
+			}
+		}
+		node = next
+	}
+
+	node.paths = append(node.paths, path)
+}
+
+type extractor struct {
+	dec *Decoder
+	fn  func(path string, value interface{}) error
+	// found holds the paths already reported, remaining counts the others
+	found     map[string]bool
+	remaining int
+}
+
+// Extract reads a document and returns the values at the given paths, keyed
+// by path. Paths use the syntax of Get and missing ones are left out. See
+// ExtractFunc for how the document is read
+func Extract(source Iterator, paths ...string) (map[string]interface{}, error) {
+	return ParseOptions{}.Extract(source, paths...)
+}
+
+// ExtractFunc reads a document and calls fn for the value at each of the
+// given paths, in the order they appear in the document. Only those values
+// are built, everything else is skipped token by token, still checking
+// brackets, commas, colons and keys. If a key occurs more than once, the
+// first match wins and later ones are ignored, which lets reading stop as
+// soon as all paths were found. Malformed paths are reported as a
+// *PathSyntaxError, errors returned by fn are passed through
+func ExtractFunc(source Iterator, paths []string, fn func(path string, value interface{}) error) error {
+	return ParseOptions{}.ExtractFunc(source, paths, fn)
+}
+
+// Extract works like the package level Extract, but applies the options
+func (opts ParseOptions) Extract(source Iterator, paths ...string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	err := opts.ExtractFunc(source, paths, func(path string, value interface{}) error {
+		values[path] = value
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// ExtractFunc works like the package level ExtractFunc, but applies the
+// options
+func (opts ParseOptions) ExtractFunc(source Iterator, paths []string, fn func(path string, value interface{}) error) error {
+	root := &extractNode{}
+	seen := map[string]bool{}
+	for _, path := range paths {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		steps, err := parseQuery(path)
+		if err != nil {
+			return err
+		}
+		root.add(steps, path)
+	}
+
+	if len(seen) == 0 {
+		return nil
+	}
+
+	ex := &extractor{
+		dec:       opts.NewDecoder(source),
+		fn:        fn,
+		found:     map[string]bool{},
+		remaining: len(seen),
+	}
+	if err := ex.walk(root); err != nil || ex.remaining == 0 {
+		return err
+	}
+
+	return ex.dec.parser.assertEnd()
+}
+
+// walk reads the next value and extracts all paths below node from it
+func (ex *extractor) walk(node *extractNode) error {
+	if len(node.paths) > 0 {
+		value, err := ex.dec.parser.parseValue()
+		if err != nil {
+			return err
+		}
+		return ex.emit(node, value)
+	}
+
+	token, err := ex.dec.source.peek()
+	if err == nil && token != nil && token.Type == PUNC {
+		if token.Value == "{" && len(node.keys) > 0 {
+			return ex.walkObject(node)
+		} else if token.Value == "[" && len(node.indices) > 0 {
+			return ex.walkArray(node)
+		}
+	}
+
+	return ex.dec.skipValue()
+}
+
+func (ex *extractor) walkObject(node *extractNode) error {
+	p := ex.dec.parser
+	if err := p.skipPunctuation("{"); err != nil {
+		return err
+	}
+
+	for i := 0; ; i++ {
+		if closed, err := p.skipIfPunctuation("}"); closed || err != nil {
+			return err
+		}
+		if i > 0 {
+			if err := p.skipPunctuation(","); err != nil {
+				return err
+			}
+		}
+
+		key, err := p.assertString()
+		if err != nil {
+			return err
+		}
+		if err := p.skipPunctuation(":"); err != nil {
+			return err
+		}
+
+		if child := node.keys[key]; child != nil {
+			err = ex.walk(child)
+		} else {
+			err = ex.dec.skipValue()
+		}
+		if err != nil || ex.remaining == 0 {
+			return err
+		}
+	}
+}
+
+func (ex *extractor) walkArray(node *extractNode) error {
+	p := ex.dec.parser
+	if err := p.skipPunctuation("["); err != nil {
+		return err
+	}
+
+	for i := 0; ; i++ {
+		if closed, err := p.skipIfPunctuation("]"); closed || err != nil {
+			return err
+		}
+		if i > 0 {
+			if err := p.skipPunctuation(","); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if child := node.indices[i]; child != nil {
+			err = ex.walk(child)
+		} else {
+			err = ex.dec.skipValue()
+		}
+		if err != nil || ex.remaining == 0 {
+			return err
+		}
+	}
+}
+
+// emit reports a decoded value for the paths ending at node, and the values
+// inside of it for the paths going deeper
+func (ex *extractor) emit(node *extractNode, value interface{}) error {
+	for _, path := range node.paths {
+		if ex.found[path] {
+			continue
+		}
+		ex.found[path] = true
+		ex.remaining--
+		if err := ex.fn(path, value); err != nil {
+			return err
+		}
+	}
+
+	switch container := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(node.keys))
+		for key := range node.keys {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if child, ok := container[key]; ok {
+				if err := ex.emit(node.keys[key], child); err != nil {
+					return err
+				}
+			}
+		}
+	case []interface{}:
+		indices := make([]int, 0, len(node.indices))
+		for index := range node.indices {
+			indices = append(indices, index)
+		}
+		sort.Ints(indices)
+
+		for _, index := range indices {
+			if index < len(container) {
+				if err := ex.emit(node.indices[index], container[index]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package gogojson
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtract(t *testing.T) {
+	assert := assert.New(t)
+
+	values, err := Extract(MakeIterator(queryJSON), "kids[1].name", "address", "address.city", `dotted\.key.\[x\]`,
+		"matrix[1]", "kids[5].name", "unknown", "name")
+	assert.Nil(err)
+	assert.Equal(map[string]interface{}{
+		"name":              "Peter",
+		"kids[1].name":      "Chris",
+		"address":           map[string]interface{}{"city": "Quahog", "zip": nil},
+		"address.city":      "Quahog",
+		`dotted\.key.\[x\]`: float64(1),
+		"matrix[1]":         []interface{}{float64(3), float64(4)},
+	}, values)
+
+	values, err = ParseOptions{Numbers: NumberString}.Extract(MakeReaderIterator(strings.NewReader(queryJSON)), "kids[1].age", "")
+	assert.Nil(err)
+	assert.Equal(Number("15.5"), values["kids[1].age"])
+	tree, _ := ParseOptions{Numbers: NumberString}.GogoJson(queryJSON)
+	assert.Equal(tree, values[""])
+
+	values, err = Extract(MakeIterator(`[1, 2]`))
+	assert.Nil(err)
+	assert.Empty(values)
+}
+
+func TestExtractFunc(t *testing.T) {
+	assert := assert.New(t)
+
+	var order []string
+	err := ExtractFunc(MakeIterator(queryJSON), []string{"matrix[0][1]", "kids[2].age", "name", "name"},
+		func(path string, value interface{}) error {
+			order = append(order, path)
+			return nil
+		})
+	assert.Nil(err)
+	assert.Equal([]string{"name", "kids[2].age", "matrix[0][1]"}, order)
+
+	stop := errors.New("stop")
+	err = ExtractFunc(MakeIterator(queryJSON), []string{"name", "kids"}, func(path string, value interface{}) error {
+		return stop
+	})
+	assert.Equal(stop, err)
+}
+
+func TestExtractDuplicateKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	// the first match wins, later duplicates neither count nor get reported
+	var order []string
+	values := map[string]interface{}{}
+	err := ExtractFunc(MakeIterator(`{"a": 1, "a": 2, "b": {"c": 3}, "b": {"c": 4}}`), []string{"a", "b.c"},
+		func(path string, value interface{}) error {
+			order = append(order, path)
+			values[path] = value
+			return nil
+		})
+	assert.Nil(err)
+	assert.Equal([]string{"a", "b.c"}, order)
+	assert.Equal(map[string]interface{}{"a": float64(1), "b.c": float64(3)}, values)
+
+	values, err = Extract(MakeIterator(`{"a": {"x": 1}, "a": {"x": 2}, "b": 3}`), "a", "a.x", "b")
+	assert.Nil(err)
+	assert.Equal(map[string]interface{}{"a": map[string]interface{}{"x": float64(1)}, "a.x": float64(1), "b": float64(3)}, values)
+}
+
+func TestExtractStopsEarly(t *testing.T) {
+	assert := assert.New(t)
+
+	// the rest of the input is never read once all paths were found
+	source := io.MultiReader(
+		strings.NewReader(`{"skipped": {"deep": [1, {"x": null}]}, "a": {"b": true}, "rest": [`),
+		iotest.ErrReader(errors.New("read too far")),
+	)
+	values, err := Extract(MakeReaderIterator(source), "a.b")
+	assert.Nil(err)
+	assert.Equal(map[string]interface{}{"a.b": true}, values)
+
+	// without all paths found the whole document is checked
+	_, err = Extract(MakeIterator(`{"a": 1} {"b": 2}`), "b")
+	assert.Equal(newSyntaxError(Position{Line: 1, Column: 10, Offset: 9}, "Unexpected token '{' after end of document"), err)
+}
+
+func TestExtractErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := map[string]string{
+		`{"a": 1 "b": 2}`:        "Expected punctuation with value ',', instead got: 'b' (line 1, column 9, offset 8)",
+		`{"a": 1, "b": [1, tru]`: "Invalid literal 'tru', expected true, false or null (line 1, column 19, offset 18)",
+		`{"a": [1, 2}`:           "Expected punctuation with value ',', instead got: '}' (line 1, column 12, offset 11)",
+		`{1: 2}`:                 "Expected string key, instead got a NUM (line 1, column 2, offset 1)",
+		`{"b": `:                 "Unexpected end of input (line 1, column 5, offset 4)",
+	}
+	for source, expected := range cases {
+		_, err := Extract(MakeIterator(source), "b")
+		assert.EqualError(err, expected, source)
+	}
+
+	paths := map[string]*PathSyntaxError{
+		".a":                      {Msg: "Unexpected '.', expected a key", Column: 1},
+		"a..b":                    {Msg: "Unexpected '.', expected a key", Column: 3},
+		"a[x]":                    {Msg: "Unexpected 'x', expected an index", Column: 3},
+		"a[1":                     {Msg: "Unexpected end of path, expected ']'", Column: 4},
+		"a[1]b":                   {Msg: "Unexpected 'b', expected '.' or '['", Column: 5},
+		`a.b\c`:                   {Msg: "Invalid escape sequence in key", Column: 4},
+		"a]":                      {Msg: "Unescaped ']' in key", Column: 2},
+		"a[99999999999999999999]": {Msg: "Invalid index '99999999999999999999'", Column: 3},
+	}
+	for path, expected := range paths {
+		_, err := Extract(MakeIterator(queryJSON), "name", path)
+		assert.Equal(expected, err, path)
+	}
+}
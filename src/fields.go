@@ -0,0 +1,203 @@
+package gogojson
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// field is a struct field as it appears in json, promoted fields of embedded
+// structs included
+type field struct {
+	name  string
+	index []int
+	typ   reflect.Type
+	// tagged is set if the name comes from a json tag
+	tagged bool
+	// omitEmpty and quoted are the omitempty and string tag options
+	omitEmpty bool
+	quoted    bool
+}
+
+// structFields are the json fields of a struct type in declaration order
+type structFields struct {
+	list   []field
+	byName map[string]int
+}
+
+// lookup finds the field for a json key, preferring an exact match over a
+// case-insensitive one
+func (fields *structFields) lookup(key string) (*field, bool) {
+	if i, ok := fields.byName[key]; ok {
+		return &fields.list[i], true
+	}
+	for i := range fields.list {
+		if strings.EqualFold(fields.list[i].name, key) {
+			return &fields.list[i], true
+		}
+	}
+
+	return nil, false
+}
+
+var fieldCache sync.Map
+
+// cachedFields returns the fields of struct type t, computing them only once
+// per type
+func cachedFields(t reflect.Type) *structFields {
+	if fields, ok := fieldCache.Load(t); ok {
+		return fields.(*structFields)
+	}
+
+	fields, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return fields.(*structFields)
+}
+
+// parseTag splits a json struct tag into the name and its options
+func parseTag(tag string) (string, map[string]bool) {
+	parts := strings.Split(tag, ",")
+	options := make(map[string]bool)
+	for _, option := range parts[1:] {
+		options[option] = true
+	}
+
+	return parts[0], options
+}
+
+// typeFields collects the fields of t breadth first, so fields of embedded
+// structs are only promoted if no shallower field has the same name. Among
+// fields of the same depth a tagged one wins, otherwise all of them are
+// dropped as ambiguous
+func typeFields(t reflect.Type) *structFields {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	fields := make([]field, 0)
+	taken := make(map[string]bool)
+	visited := make(map[reflect.Type]bool)
+	next := []embedded{{typ: t}}
+
+	for len(next) > 0 {
+		current := next
+		next = nil
+		level := make(map[string][]field)
+		order := make([]string, 0)
+
+		for _, emb := range current {
+			if visited[emb.typ] {
+				continue
+			}
+			visited[emb.typ] = true
+
+			for i := 0; i < emb.typ.NumField(); i++ {
+				sf := emb.typ.Field(i)
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+
+				// unexported embedded structs can still promote exported fields
+				if !sf.IsExported() && !(sf.Anonymous && ft.Kind() == reflect.Struct) {
+					continue
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, options := parseTag(tag)
+
+				index := make([]int, len(emb.index)+1)
+				copy(index, emb.index)
+				index[len(emb.index)] = i
+
+				if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+					next = append(next, embedded{typ: ft, index: index})
+					continue
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				f := field{
+					name:      name,
+					index:     index,
+					typ:       sf.Type,
+					tagged:    name != "",
+					omitEmpty: options["omitempty"],
+					quoted:    options["string"] && isQuotable(sf.Type),
+				}
+				if f.name == "" {
+					f.name = sf.Name
+				}
+
+				if _, ok := level[f.name]; !ok {
+					order = append(order, f.name)
+				}
+				level[f.name] = append(level[f.name], f)
+			}
+		}
+
+		for _, name := range order {
+			if taken[name] {
+				continue
+			}
+			taken[name] = true
+
+			if winner, ok := dominantField(level[name]); ok {
+				fields = append(fields, winner)
+			}
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i].index, fields[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+
+	byName := make(map[string]int, len(fields))
+	for i, f := range fields {
+		byName[f.name] = i
+	}
+
+	return &structFields{list: fields, byName: byName}
+}
+
+// dominantField picks the field of one name among fields of the same depth
+func dominantField(candidates []field) (field, bool) {
+	if len(candidates) == 1 {
+		return candidates[0], true
+	}
+
+	tagged := make([]field, 0)
+	for _, f := range candidates {
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+
+	return field{}, false
+}
+
+// isQuotable reports whether the string tag option applies to values of t
+func isQuotable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
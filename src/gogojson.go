@@ -1,28 +1,61 @@
+// Package gogojson converts json documents into go values and back.
+//
+// GogoJson parses a document into generic values: objects are represented as
+// map[string]interface{}, arrays as []interface{}, numbers as float64, and
+// strings, booleans and null as string, bool and nil. Unmarshal decodes into
+// typed values like structs instead. ParseOptions changes how values are
+// represented.
 package gogojson
 
-/*
-var numCheck = regexp.MustCompile("[0-9]")
+import (
+	"reflect"
+)
 
-// var numCheck = regexp.MustCompile("[0-9]")
+// Unmarshal parses a json document and stores it in the value v points to.
+// Structs are matched by field name or json tag, ignoring case if there is
+// no exact match, and unknown keys are skipped. Decoding into an interface{}
+// stores the generic value GogoJson would return. Types implementing
+// Unmarshaler or encoding.TextUnmarshaler decode themselves. Malformed input is
+// reported as a *SyntaxError, values of the wrong type as an
+// *UnmarshalTypeError
+func Unmarshal(data []byte, v interface{}) error {
+	return ParseOptions{}.Unmarshal(data, v)
+}
 
-func GogoJson(source string) map[string]interface{} {
-	json := MakeIterator(source)
-	out := make(map[string]interface{})
-	skipWhitespace(json)
+// GogoJson parses a json document, whose top level value may be of any json
+// type, into generic values. Malformed input is reported as a *SyntaxError
+func GogoJson(source string) (interface{}, error) {
+	return ParseOptions{}.GogoJson(source)
+}
 
-	if json.Peek() == "{" {
-		json.Next()
+// Unmarshal works like the package level Unmarshal, but applies the options.
+// Numbers only affects values stored in interfaces
+func (opts ParseOptions) Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &InvalidUnmarshalError{Type: reflect.TypeOf(v)}
 	}
 
-	for json.Peek() != "}" {
-		skipWhitespace(json)
-		key, value := parseKeyValue(json)
-		skipWhitespace(json)
-		skipComma(json)
+	// numbers are kept exact until their go type is known
+	dec := &decoder{numbers: opts.Numbers}
+	opts.Numbers = NumberString
+	tree, err := opts.GogoJson(string(data))
+	if err != nil {
+		return err
+	}
+
+	return dec.decode("$", tree, rv.Elem())
+}
 
-		out[key] = value
+// GogoJson works like the package level GogoJson, but applies the options
+func (opts ParseOptions) GogoJson(source string) (interface{}, error) {
+	if opts.Parallel > 1 {
+		if value, ok := opts.parseParallel(source); ok {
+			return value, nil
+		}
 	}
 
-	return out
+	// tokens are read lazily, so every error happens inside of the parser
+	// and carries the pointer of the value it was found in
+	return opts.parseDocument(&lexerSource{lexer: lexer{MakeIterator(source)}})
 }
-*/
@@ -1,6 +1,11 @@
 package gogojson
 
-/*
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
 const basicJson = `{
   "name": "John",
   "age": 34,
@@ -9,10 +14,59 @@ const basicJson = `{
 
 func TestParser(t *testing.T) {
 	assert := assert.New(t)
-	parsed := GogoJson(basicJson)
+	parsed, err := GogoJson(basicJson)
+
+	assert.Nil(err)
+	assert.Equal(map[string]interface{}{
+		"name": "John",
+		"age":  float64(34),
+		"male": true,
+	}, parsed)
+}
+
+func TestUnmarshal(t *testing.T) {
+	assert := assert.New(t)
+	documents := map[string]interface{}{
+		`{"a": [1, {}]}`: map[string]interface{}{"a": []interface{}{float64(1), map[string]interface{}{}}},
+		`[1, "two"]`:     []interface{}{float64(1), "two"},
+		` "hello" `:      "hello",
+		`42`:             float64(42),
+		`true`:           true,
+		`null`:           nil,
+	}
+
+	for source, expected := range documents {
+		var parsed interface{}
+		err := Unmarshal([]byte(source), &parsed)
+
+		assert.Nil(err, source)
+		assert.Equal(expected, parsed, source)
+	}
+}
+
+func TestParserErrors(t *testing.T) {
+	assert := assert.New(t)
+	malformed := []string{
+		``,
+		`   `,
+		`{`,
+		`{"name"`,
+		`{"name":`,
+		`{"name": "John"`,
+		`{"name": "John",}`,
+		`{"name": "Jo`,
+		`{"name": }`,
+		`{"a": [1, 2}`,
+		`{"a": 1} {`,
+		`[1, 2] 3`,
+		`,`,
+		`!`,
+	}
+
+	for _, source := range malformed {
+		parsed, err := GogoJson(source)
 
-	assert.Equal(parsed["name"], "John")
-	assert.Equal(parsed["age"], 34)
-	assert.Equal(parsed["male"], true)
+		assert.Nil(parsed, source)
+		assert.IsType(&SyntaxError{}, err, source)
+	}
 }
-*/
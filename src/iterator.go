@@ -1,56 +1,123 @@
 package gogojson
 
+import (
+	"strings"
+	"unicode/utf8"
+)
+
 // Iterator is an interface that provides an easy way to interact with json input
 // from whatever source
 type Iterator interface {
+	// Next consumes and returns the next character, "" at the end of input
 	Next() string
+	// Peek returns the next character without consuming it
 	Peek() string
 	Eof() bool
+	// GetPosition returns the position of the next character
+	GetPosition() Position
+	// Err returns the error that ended the input early, if any
+	Err() error
 	// Suffocate(string, ...string)
 }
 
+// IteratorOptions controls how iterators decode their input. The zero value
+// is what MakeIterator and MakeReaderIterator use
+type IteratorOptions struct {
+	// ReplaceInvalidUTF8 turns invalid utf-8 into U+FFFD instead of stopping
+	// with a *SyntaxError
+	ReplaceInvalidUTF8 bool
+	// ByteColumns counts columns in bytes instead of characters
+	ByteColumns bool
+}
+
+const byteOrderMark = "\uFEFF"
+
+// decode checks a decoded rune and returns the character it stands for, or
+// the error for invalid utf-8
+func (opts IteratorOptions) decode(r rune, size int, pos Position) (string, error) {
+	if r != utf8.RuneError || size != 1 {
+		return string(r), nil
+	} else if opts.ReplaceInvalidUTF8 {
+		return string(utf8.RuneError), nil
+	}
+
+	return "", newSyntaxError(pos, "Invalid UTF-8 encoding")
+}
+
+// advance moves pos past char, which is size bytes long
+func (opts IteratorOptions) advance(pos *Position, char string, size uint64) {
+	pos.Offset += size
+
+	if char == "\n" {
+		pos.Column = 1
+		pos.Line += 1
+	} else if opts.ByteColumns {
+		pos.Column += size
+	} else {
+		pos.Column += 1
+	}
+}
+
+// StringIterator iterates over the utf-8 characters of a string
 type StringIterator struct {
 	source  string
 	current uint64
 	line    uint64
 	row     uint64
 	length  uint64
+	opts    IteratorOptions
+	err     error
 }
 
-func (iter *StringIterator) Next() string {
-	if iter.Eof() {
-		return ""
+// peek returns the next character and its size in bytes
+func (iter *StringIterator) peek() (string, uint64) {
+	if iter.length <= iter.current || iter.err != nil {
+		return "", 0
 	}
 
-	char := string(iter.source[iter.current])
-	iter.current += 1
-
-	if char == "\n" {
-		iter.row = 0
-		iter.line += 1
-	} else {
-		iter.row += 1
+	// ascii needs no decoding
+	if c := iter.source[iter.current]; c < utf8.RuneSelf {
+		return iter.source[iter.current : iter.current+1], 1
 	}
 
-	return char
+	r, size := utf8.DecodeRuneInString(iter.source[iter.current:])
+	char, err := iter.opts.decode(r, size, iter.GetPosition())
+	iter.err = err
+
+	return char, uint64(size)
 }
 
-func (iter *StringIterator) Peek() string {
-	if iter.Eof() {
+func (iter *StringIterator) Next() string {
+	char, size := iter.peek()
+	if char == "" {
 		return ""
 	}
 
-	return string(iter.source[iter.current])
+	pos := iter.GetPosition()
+	iter.opts.advance(&pos, char, size)
+	iter.line, iter.row, iter.current = pos.Line, pos.Column, pos.Offset
+
+	return char
+}
+
+func (iter *StringIterator) Peek() string {
+	char, _ := iter.peek()
+	return char
 }
 
 func (iter *StringIterator) Eof() bool {
-	return iter.length <= iter.current
+	return iter.Peek() == ""
 }
 
 func (iter *StringIterator) HasNext() bool {
 	return !iter.Eof()
 }
 
+// Err returns the *SyntaxError for invalid utf-8 that ended the input
+func (iter *StringIterator) Err() error {
+	return iter.err
+}
+
 /*func (iter *StringIterator) Suffocate(msg string, additional ...string) {
 	fmt.Printf("Error happened in iterator")
 }*/
@@ -63,12 +130,37 @@ func (iter *StringIterator) GetRow() uint64 {
 	return iter.row
 }
 
+func (iter *StringIterator) GetOffset() uint64 {
+	return iter.current
+}
+
+// GetPosition returns the position of the next character
+func (iter *StringIterator) GetPosition() Position {
+	return Position{
+		Line:   iter.line,
+		Column: iter.row,
+		Offset: iter.current,
+	}
+}
+
 func MakeIterator(source string) *StringIterator {
+	return IteratorOptions{}.MakeIterator(source)
+}
+
+// MakeIterator works like the package level MakeIterator, but applies the
+// options. A leading byte order mark is skipped
+func (opts IteratorOptions) MakeIterator(source string) *StringIterator {
+	current := 0
+	if strings.HasPrefix(source, byteOrderMark) {
+		current = len(byteOrderMark)
+	}
+
 	return &StringIterator{
 		source:  source,
-		current: 0,
+		current: uint64(current),
 		line:    1,
 		row:     1,
 		length:  uint64(len(source)),
+		opts:    opts,
 	}
 }
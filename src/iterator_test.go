@@ -54,12 +54,96 @@ func TestNewline(t *testing.T) {
 	assert.Equal(iterator.GetLine(), uint64(1))
 
 	assert.Equal(iterator.Next(), "\n")
-	assert.Equal(iterator.GetRow(), uint64(0))
+	assert.Equal(iterator.GetRow(), uint64(1))
 	assert.Equal(iterator.GetLine(), uint64(2))
 
 	assert.Equal(iterator.Next(), "c")
-	assert.Equal(iterator.GetRow(), uint64(1))
+	assert.Equal(iterator.GetRow(), uint64(2))
 	assert.Equal(iterator.GetLine(), uint64(2))
 
 	assert.Equal(iterator.Next(), "")
 }
+
+func TestPosition(t *testing.T) {
+	assert := assert.New(t)
+	iterator := MakeIterator(newlineString)
+
+	assert.Equal(Position{Line: 1, Column: 1, Offset: 0}, iterator.GetPosition())
+
+	iterator.Next()
+	iterator.Next()
+	iterator.Next()
+	assert.Equal(Position{Line: 2, Column: 1, Offset: 3}, iterator.GetPosition())
+	assert.Equal(iterator.GetOffset(), uint64(3))
+}
+
+func TestUTF8(t *testing.T) {
+	assert := assert.New(t)
+	iterator := MakeIterator("äb€\n😀")
+
+	assert.Equal(iterator.Peek(), "ä")
+	assert.Equal(iterator.Next(), "ä")
+	assert.Equal(Position{Line: 1, Column: 2, Offset: 2}, iterator.GetPosition())
+
+	assert.Equal(iterator.Next(), "b")
+	assert.Equal(iterator.Next(), "€")
+	assert.Equal(Position{Line: 1, Column: 4, Offset: 6}, iterator.GetPosition())
+
+	assert.Equal(iterator.Next(), "\n")
+	assert.Equal(iterator.Next(), "😀")
+	assert.Equal(Position{Line: 2, Column: 2, Offset: 11}, iterator.GetPosition())
+	assert.True(iterator.Eof())
+	assert.Nil(iterator.Err())
+}
+
+func TestByteColumns(t *testing.T) {
+	assert := assert.New(t)
+	iterator := IteratorOptions{ByteColumns: true}.MakeIterator("ä€x")
+
+	iterator.Next()
+	iterator.Next()
+	assert.Equal(Position{Line: 1, Column: 6, Offset: 5}, iterator.GetPosition())
+}
+
+func TestByteOrderMark(t *testing.T) {
+	assert := assert.New(t)
+	iterator := MakeIterator("\uFEFF{}")
+
+	assert.Equal(Position{Line: 1, Column: 1, Offset: 3}, iterator.GetPosition())
+	assert.Equal(iterator.Next(), "{")
+
+	// only a leading mark is skipped
+	tokens, err := Tokenize(MakeIterator("\uFEFF\"\uFEFF\""))
+	assert.Nil(err)
+	assert.Equal("\uFEFF", tokens[0].Value)
+}
+
+func TestInvalidUTF8(t *testing.T) {
+	assert := assert.New(t)
+	iterator := MakeIterator("a\xffb")
+
+	assert.Equal(iterator.Next(), "a")
+	assert.Equal(iterator.Next(), "")
+	assert.True(iterator.Eof())
+
+	expected := &SyntaxError{
+		Msg:      "Invalid UTF-8 encoding",
+		Position: Position{Line: 1, Column: 2, Offset: 1},
+	}
+	assert.Equal(expected, iterator.Err())
+
+	_, err := Tokenize(MakeIterator("[\"a\xffb\"]"))
+	expected.Position = Position{Line: 1, Column: 4, Offset: 3}
+	assert.Equal(expected, err)
+}
+
+func TestReplaceInvalidUTF8(t *testing.T) {
+	assert := assert.New(t)
+	iterator := IteratorOptions{ReplaceInvalidUTF8: true}.MakeIterator("a\xffb")
+
+	assert.Equal(iterator.Next(), "a")
+	assert.Equal(iterator.Next(), "�")
+	assert.Equal(Position{Line: 1, Column: 3, Offset: 2}, iterator.GetPosition())
+	assert.Equal(iterator.Next(), "b")
+	assert.Nil(iterator.Err())
+}
@@ -0,0 +1,787 @@
+package gogojson
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Path is a compiled JSONPath expression. It is safe for concurrent use
+//
+// Supported are the root $, child segments .name, .* and bracketed
+// selectors, descendant segments .., and as selectors quoted names,
+// wildcards, indices counting from the end when negative, slices
+// [start:end:step], unions like [0,'a'] and filters [?(@.price > 10)].
+// Filters compare values with ==, !=, <, <=, > and >=, combine tests with
+// &&, || and !, and test for existence of a path like [?(@.isbn)]
+type Path struct {
+	expr     string
+	segments []pathSegment
+}
+
+// Compile parses a JSONPath expression. Malformed expressions are reported
+// as a *PathSyntaxError
+func Compile(expr string) (*Path, error) {
+	p := &pathParser{expr: expr}
+	if !p.consume("$") {
+		return nil, p.errorf("Expected '$' at the start of the path")
+	}
+
+	segments, err := p.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(expr) {
+		return nil, p.errorf("Unexpected '%c'", p.peekRune())
+	}
+
+	return &Path{expr: expr, segments: segments}, nil
+}
+
+// String returns the expression the path was compiled from
+func (path *Path) String() string {
+	return path.expr
+}
+
+// Select returns all values of a parsed tree the path matches, in document
+// order. Object members are visited in sorted key order
+func (path *Path) Select(tree interface{}) []interface{} {
+	return selectSegments(path.segments, tree, tree)
+}
+
+func selectSegments(segments []pathSegment, node, root interface{}) []interface{} {
+	nodes := []interface{}{node}
+	for _, segment := range segments {
+		var selected []interface{}
+		for _, node := range nodes {
+			selected = segment.apply(node, root, selected)
+		}
+		nodes = selected
+	}
+
+	return nodes
+}
+
+// pathSegment applies its selectors to a node, or with descendant set to
+// the node and everything nested in it
+type pathSegment struct {
+	descendant bool
+	selectors  []pathSelector
+}
+
+func (segment *pathSegment) apply(node, root interface{}, out []interface{}) []interface{} {
+	for _, selector := range segment.selectors {
+		out = selector.apply(node, root, out)
+	}
+
+	if segment.descendant {
+		for _, child := range children(node) {
+			out = segment.apply(child, root, out)
+		}
+	}
+
+	return out
+}
+
+// children returns the elements of an array or the values of an object
+func children(node interface{}) []interface{} {
+	switch value := node.(type) {
+	case []interface{}:
+		return value
+	case map[string]interface{}:
+		values := make([]interface{}, 0, len(value))
+		for _, key := range sortedKeys(value) {
+			values = append(values, value[key])
+		}
+		return values
+	}
+
+	return nil
+}
+
+// pathSelector appends the values it selects from a node to out
+type pathSelector interface {
+	apply(node, root interface{}, out []interface{}) []interface{}
+}
+
+type nameSelector struct {
+	name string
+}
+
+func (s *nameSelector) apply(node, root interface{}, out []interface{}) []interface{} {
+	if object, ok := node.(map[string]interface{}); ok {
+		if value, ok := object[s.name]; ok {
+			out = append(out, value)
+		}
+	}
+
+	return out
+}
+
+type wildcardSelector struct{}
+
+func (s *wildcardSelector) apply(node, root interface{}, out []interface{}) []interface{} {
+	return append(out, children(node)...)
+}
+
+type indexSelector struct {
+	index int
+}
+
+func (s *indexSelector) apply(node, root interface{}, out []interface{}) []interface{} {
+	array, ok := node.([]interface{})
+	if !ok {
+		return out
+	}
+
+	index := s.index
+	if index < 0 {
+		index += len(array)
+	}
+	if index >= 0 && index < len(array) {
+		out = append(out, array[index])
+	}
+
+	return out
+}
+
+// sliceSelector selects like a python slice, nil bounds default to the
+// start or end depending on the direction of step
+type sliceSelector struct {
+	start, end *int
+	step       int
+}
+
+func (s *sliceSelector) apply(node, root interface{}, out []interface{}) []interface{} {
+	array, ok := node.([]interface{})
+	if !ok || s.step == 0 {
+		return out
+	}
+
+	n := len(array)
+	bound := func(i *int, fallback, min, max int) int {
+		if i == nil {
+			return fallback
+		}
+		value := *i
+		if value < 0 {
+			value += n
+		}
+		if value < min {
+			return min
+		} else if value > max {
+			return max
+		}
+		return value
+	}
+
+	// a step longer than the array selects a single element either way, and
+	// clamping it keeps i from overflowing
+	step := s.step
+	if step > n {
+		step = n
+	} else if step < -n {
+		step = -n
+	}
+
+	if s.step > 0 {
+		for i := bound(s.start, 0, 0, n); i < bound(s.end, n, 0, n); i += step {
+			out = append(out, array[i])
+		}
+	} else {
+		for i := bound(s.start, n-1, -1, n-1); i > bound(s.end, -1, -1, n-1); i += step {
+			out = append(out, array[i])
+		}
+	}
+
+	return out
+}
+
+type filterSelector struct {
+	filter filterExpr
+}
+
+func (s *filterSelector) apply(node, root interface{}, out []interface{}) []interface{} {
+	for _, child := range children(node) {
+		if s.filter.test(child, root) {
+			out = append(out, child)
+		}
+	}
+
+	return out
+}
+
+// filterExpr is a boolean expression of a filter selector, evaluated with
+// @ being the current node and $ the root of the tree
+type filterExpr interface {
+	test(current, root interface{}) bool
+}
+
+type orExpr struct {
+	left, right filterExpr
+}
+
+func (e *orExpr) test(current, root interface{}) bool {
+	return e.left.test(current, root) || e.right.test(current, root)
+}
+
+type andExpr struct {
+	left, right filterExpr
+}
+
+func (e *andExpr) test(current, root interface{}) bool {
+	return e.left.test(current, root) && e.right.test(current, root)
+}
+
+type notExpr struct {
+	expr filterExpr
+}
+
+func (e *notExpr) test(current, root interface{}) bool {
+	return !e.expr.test(current, root)
+}
+
+// existsExpr is true if its query selects anything
+type existsExpr struct {
+	query *queryOperand
+}
+
+func (e *existsExpr) test(current, root interface{}) bool {
+	return len(e.query.nodes(current, root)) > 0
+}
+
+type compareExpr struct {
+	op          string
+	left, right filterOperand
+}
+
+func (e *compareExpr) test(current, root interface{}) bool {
+	left, leftOk := e.left.value(current, root)
+	right, rightOk := e.right.value(current, root)
+
+	switch e.op {
+	case "==":
+		return compareEqual(left, leftOk, right, rightOk)
+	case "!=":
+		return !compareEqual(left, leftOk, right, rightOk)
+	case "<":
+		return compareLess(left, leftOk, right, rightOk)
+	case "<=":
+		return compareLess(left, leftOk, right, rightOk) || compareEqual(left, leftOk, right, rightOk)
+	case ">":
+		return compareLess(right, rightOk, left, leftOk)
+	case ">=":
+		return compareLess(right, rightOk, left, leftOk) || compareEqual(left, leftOk, right, rightOk)
+	}
+
+	return false
+}
+
+// compareEqual treats numbers of all representations alike. Two missing
+// values are equal, a missing and a present one are not
+func compareEqual(left interface{}, leftOk bool, right interface{}, rightOk bool) bool {
+	if !leftOk || !rightOk {
+		return leftOk == rightOk
+	}
+
+	if l, ok := floatValue(left); ok {
+		r, ok := floatValue(right)
+		return ok && l == r
+	}
+
+	return reflect.DeepEqual(left, right)
+}
+
+// compareLess orders numbers and strings, all other values are unordered
+func compareLess(left interface{}, leftOk bool, right interface{}, rightOk bool) bool {
+	if !leftOk || !rightOk {
+		return false
+	}
+
+	if l, ok := floatValue(left); ok {
+		r, ok := floatValue(right)
+		return ok && l < r
+	}
+	if l, ok := left.(string); ok {
+		r, ok := right.(string)
+		return ok && l < r
+	}
+
+	return false
+}
+
+// filterOperand is a side of a comparison. value reports false if there is
+// nothing to compare, like for queries that select no or several nodes
+type filterOperand interface {
+	value(current, root interface{}) (interface{}, bool)
+}
+
+type literalOperand struct {
+	literal interface{}
+}
+
+func (o *literalOperand) value(current, root interface{}) (interface{}, bool) {
+	return o.literal, true
+}
+
+// queryOperand is a path relative to the current node (@) or the root ($)
+type queryOperand struct {
+	relative bool
+	segments []pathSegment
+}
+
+func (o *queryOperand) nodes(current, root interface{}) []interface{} {
+	if o.relative {
+		return selectSegments(o.segments, current, root)
+	}
+
+	return selectSegments(o.segments, root, root)
+}
+
+func (o *queryOperand) value(current, root interface{}) (interface{}, bool) {
+	nodes := o.nodes(current, root)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+
+	return nodes[0], true
+}
+
+// pathParser is a recursive descent parser of JSONPath expressions
+type pathParser struct {
+	expr string
+	pos  int
+}
+
+func (p *pathParser) errorf(format string, args ...interface{}) error {
+	return &PathSyntaxError{
+		Msg:    fmt.Sprintf(format, args...),
+		Column: utf8.RuneCountInString(p.expr[:p.pos]) + 1,
+	}
+}
+
+func (p *pathParser) peekRune() rune {
+	r, _ := utf8.DecodeRuneInString(p.expr[p.pos:])
+	return r
+}
+
+// consume skips prefix if the remaining expression starts with it
+func (p *pathParser) consume(prefix string) bool {
+	if strings.HasPrefix(p.expr[p.pos:], prefix) {
+		p.pos += len(prefix)
+		return true
+	}
+
+	return false
+}
+
+func (p *pathParser) skipSpace() {
+	for p.pos < len(p.expr) && strings.IndexByte(" \t\n\r", p.expr[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// unexpected reports the character at the current position
+func (p *pathParser) unexpected(expected string) error {
+	if p.pos >= len(p.expr) {
+		return p.errorf("Unexpected end of path, expected %s", expected)
+	}
+
+	return p.errorf("Unexpected '%c', expected %s", p.peekRune(), expected)
+}
+
+// parseSegments reads segments up to the first character that can't start
+// one
+func (p *pathParser) parseSegments() ([]pathSegment, error) {
+	var segments []pathSegment
+
+	for p.pos < len(p.expr) {
+		var segment pathSegment
+		var err error
+
+		switch {
+		case p.consume(".."):
+			segment.descendant = true
+			if p.consume("[") {
+				segment.selectors, err = p.parseBracket()
+			} else {
+				segment.selectors, err = p.parseShorthand()
+			}
+		case p.consume("."):
+			segment.selectors, err = p.parseShorthand()
+		case p.consume("["):
+			segment.selectors, err = p.parseBracket()
+		default:
+			return segments, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, segment)
+	}
+
+	return segments, nil
+}
+
+// parseShorthand reads the name or wildcard following a dot
+func (p *pathParser) parseShorthand() ([]pathSelector, error) {
+	if p.consume("*") {
+		return []pathSelector{&wildcardSelector{}}, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.expr) {
+		r, size := utf8.DecodeRuneInString(p.expr[p.pos:])
+		if !isPathNameRune(r) {
+			break
+		}
+		p.pos += size
+	}
+	if p.pos == start {
+		return nil, p.unexpected("a name or '*'")
+	}
+
+	return []pathSelector{&nameSelector{name: p.expr[start:p.pos]}}, nil
+}
+
+func isPathNameRune(r rune) bool {
+	return r == '_' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+		(r >= '0' && r <= '9') || (r >= utf8.RuneSelf && r != utf8.RuneError)
+}
+
+// parseBracket reads the comma separated selectors following an opening
+// bracket, up to the closing one
+func (p *pathParser) parseBracket() ([]pathSelector, error) {
+	var selectors []pathSelector
+
+	for {
+		p.skipSpace()
+		selector, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+
+		p.skipSpace()
+		if p.consume("]") {
+			return selectors, nil
+		}
+		if !p.consume(",") {
+			return nil, p.unexpected("',' or ']'")
+		}
+	}
+}
+
+func (p *pathParser) parseSelector() (pathSelector, error) {
+	if p.pos >= len(p.expr) {
+		return nil, p.unexpected("a selector")
+	}
+
+	switch c := p.expr[p.pos]; {
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return &nameSelector{name: name}, nil
+	case c == '*':
+		p.pos++
+		return &wildcardSelector{}, nil
+	case c == '?':
+		p.pos++
+		p.skipSpace()
+		filter, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return &filterSelector{filter: filter}, nil
+	case c == '-' || c == ':' || (c >= '0' && c <= '9'):
+		return p.parseIndexOrSlice()
+	}
+
+	return nil, p.unexpected("a selector")
+}
+
+// parseIndexOrSlice reads an index like -1 or a slice like 1:5:2
+func (p *pathParser) parseIndexOrSlice() (pathSelector, error) {
+	var bounds [3]*int
+	colons := 0
+
+	for {
+		p.skipSpace()
+		if p.pos < len(p.expr) && (p.expr[p.pos] == '-' || (p.expr[p.pos] >= '0' && p.expr[p.pos] <= '9')) {
+			i, err := p.parseInt()
+			if err != nil {
+				return nil, err
+			}
+			bounds[colons] = &i
+		}
+
+		p.skipSpace()
+		if colons == 2 || !p.consume(":") {
+			break
+		}
+		colons++
+	}
+
+	if colons == 0 {
+		if bounds[0] == nil {
+			return nil, p.unexpected("an index")
+		}
+		return &indexSelector{index: *bounds[0]}, nil
+	}
+
+	step := 1
+	if bounds[2] != nil {
+		step = *bounds[2]
+	}
+	return &sliceSelector{start: bounds[0], end: bounds[1], step: step}, nil
+}
+
+func (p *pathParser) parseInt() (int, error) {
+	start := p.pos
+	p.consume("-")
+	for p.pos < len(p.expr) && p.expr[p.pos] >= '0' && p.expr[p.pos] <= '9' {
+		p.pos++
+	}
+
+	literal := p.expr[start:p.pos]
+	i, err := strconv.Atoi(literal)
+	if err != nil {
+		p.pos = start
+		return 0, p.errorf("Invalid integer '%s'", literal)
+	}
+
+	return i, nil
+}
+
+// parseString reads a single or double quoted string, which may contain the
+// escape sequences of json strings
+func (p *pathParser) parseString() (string, error) {
+	quote := p.expr[p.pos]
+	p.pos++
+
+	var out strings.Builder
+	for p.pos < len(p.expr) {
+		c := p.expr[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return out.String(), nil
+		case c == '\\':
+			if err := p.parseStringEscape(&out); err != nil {
+				return "", err
+			}
+		default:
+			out.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	return "", p.unexpected(fmt.Sprintf("closing %c", quote))
+}
+
+func (p *pathParser) parseStringEscape(out *strings.Builder) error {
+	p.pos++
+	if p.pos >= len(p.expr) {
+		return p.unexpected("an escape sequence")
+	}
+
+	c := p.expr[p.pos]
+	p.pos++
+	switch c {
+	case '"', '\'', '\\', '/':
+		out.WriteByte(c)
+	case 'b':
+		out.WriteByte('\b')
+	case 'f':
+		out.WriteByte('\f')
+	case 'n':
+		out.WriteByte('\n')
+	case 'r':
+		out.WriteByte('\r')
+	case 't':
+		out.WriteByte('\t')
+	case 'u':
+		r, err := p.parseHex()
+		if err != nil {
+			return err
+		}
+		if utf16.IsSurrogate(r) && p.consume(`\u`) {
+			low, err := p.parseHex()
+			if err != nil {
+				return err
+			}
+			r = utf16.DecodeRune(r, low)
+		}
+		out.WriteRune(r)
+	default:
+		p.pos -= 2
+		return p.errorf("Invalid escape sequence '\\%c'", c)
+	}
+
+	return nil
+}
+
+func (p *pathParser) parseHex() (rune, error) {
+	if p.pos+4 > len(p.expr) {
+		return 0, p.errorf("Expected 4 hex digits")
+	}
+
+	value, err := strconv.ParseUint(p.expr[p.pos:p.pos+4], 16, 32)
+	if err != nil {
+		return 0, p.errorf("Expected 4 hex digits")
+	}
+	p.pos += 4
+
+	return rune(value), nil
+}
+
+// parseOr and the functions it calls parse filter expressions, from the
+// lowest precedence to the highest
+func (p *pathParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.skipSpace(); p.consume("||"); p.skipSpace() {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orExpr{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *pathParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.skipSpace(); p.consume("&&"); p.skipSpace() {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &andExpr{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *pathParser) parseNot() (filterExpr, error) {
+	p.skipSpace()
+	if strings.HasPrefix(p.expr[p.pos:], "!") && !strings.HasPrefix(p.expr[p.pos:], "!=") {
+		p.pos++
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{expr: expr}, nil
+	}
+
+	return p.parseComparison()
+}
+
+var comparisonOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func (p *pathParser) parseComparison() (filterExpr, error) {
+	p.skipSpace()
+	if p.consume("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.consume(")") {
+			return nil, p.unexpected("')'")
+		}
+		return expr, nil
+	}
+
+	start := p.pos
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	for _, op := range comparisonOperators {
+		if p.consume(op) {
+			p.skipSpace()
+			right, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			return &compareExpr{op: op, left: left, right: right}, nil
+		}
+	}
+
+	query, ok := left.(*queryOperand)
+	if !ok {
+		p.pos = start
+		return nil, p.errorf("Expected a comparison or a path to test for existence")
+	}
+
+	return &existsExpr{query: query}, nil
+}
+
+func (p *pathParser) parseOperand() (filterOperand, error) {
+	if p.pos >= len(p.expr) {
+		return nil, p.unexpected("a value")
+	}
+
+	switch c := p.expr[p.pos]; {
+	case c == '@' || c == '$':
+		p.pos++
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+		return &queryOperand{relative: c == '@', segments: segments}, nil
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return &literalOperand{literal: s}, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	case p.consume("true"):
+		return &literalOperand{literal: true}, nil
+	case p.consume("false"):
+		return &literalOperand{literal: false}, nil
+	case p.consume("null"):
+		return &literalOperand{literal: nil}, nil
+	}
+
+	return nil, p.unexpected("a value")
+}
+
+func (p *pathParser) parseNumber() (filterOperand, error) {
+	start := p.pos
+	for p.pos < len(p.expr) && strings.IndexByte("-+.eE0123456789", p.expr[p.pos]) >= 0 {
+		p.pos++
+	}
+
+	literal := p.expr[start:p.pos]
+	if !isNumberLiteral(literal) {
+		p.pos = start
+		return nil, p.errorf("Invalid number '%s'", literal)
+	}
+	value, err := strconv.ParseFloat(literal, 64)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("Number '%s' is out of range", literal)
+	}
+
+	return &literalOperand{literal: value}, nil
+}
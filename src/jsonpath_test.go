@@ -0,0 +1,129 @@
+package gogojson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const storeJSON = `{
+  "store": {
+    "book": [
+      {"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+      {"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+      {"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+      {"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+    ],
+    "bicycle": {"color": "red", "price": 19.95}
+  },
+  "limit": 10,
+  "odd key": ["x", "y"]
+}`
+
+func TestJSONPathSelect(t *testing.T) {
+	assert := assert.New(t)
+	tree, err := GogoJson(storeJSON)
+	assert.Nil(err)
+
+	cases := map[string][]interface{}{
+		`$.store.book[*].author`:                        {"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"},
+		`$..author`:                                     {"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"},
+		`$.store.*.color`:                               {"red"},
+		`$.store..price`:                                {19.95, 8.95, 12.99, 8.99, 22.99},
+		`$..book[2].title`:                              {"Moby Dick"},
+		`$..book[-1].title`:                             {"The Lord of the Rings"},
+		`$..book[0,1].price`:                            {8.95, 12.99},
+		`$..book[:2].price`:                             {8.95, 12.99},
+		`$..book[1:].price`:                             {12.99, 8.99, 22.99},
+		`$..book[::-2].price`:                           {22.99, 12.99},
+		`$..book[-2:10].price`:                          {8.99, 22.99},
+		`$..book[?(@.isbn)].title`:                      {"Moby Dick", "The Lord of the Rings"},
+		`$..book[?(@.price < 10)].price`:                {8.95, 8.99},
+		`$..book[?(@.price > $.limit)].price`:           {12.99, 22.99},
+		`$..book[?(@.price>=12.99 && !(@.isbn))].title`: {"Sword of Honour"},
+		`$..book[?(@.author == 'Nigel Rees' || @.price == 22.99)].price`: {8.95, 22.99},
+		`$..book[? @.category != "fiction"].price`:                       {8.95},
+		`$..[?(@.color == "red")].price`:                                 {19.95},
+		`$['odd key'][1]`:                                                {"y"},
+		`$["store"]['bicycle', "missing"].color`:                         {"red"},
+		`$.limit`:                                                        {float64(10)},
+		`$.limit[0]`:                                                     nil,
+		`$.missing..x`:                                                   nil,
+		`$..book[4]`:                                                     nil,
+		`$..book[::0]`:                                                   nil,
+	}
+	for expr, expected := range cases {
+		path, err := Compile(expr)
+		if !assert.Nil(err, expr) {
+			continue
+		}
+		assert.Equal(expected, path.Select(tree), expr)
+		assert.Equal(expr, path.String())
+	}
+
+	path, err := Compile(`$`)
+	assert.Nil(err)
+	assert.Equal([]interface{}{tree}, path.Select(tree))
+}
+
+func TestJSONPathSliceExtremeSteps(t *testing.T) {
+	assert := assert.New(t)
+	array := []interface{}{1., 2., 3.}
+
+	cases := map[string][]interface{}{
+		"$[1::9223372036854775807]":                                       {2.},
+		"$[::9223372036854775807]":                                        {1.},
+		"$[::-9223372036854775808]":                                       {3.},
+		"$[1::-9223372036854775808]":                                      {2.},
+		"$[-9223372036854775808:9223372036854775807:9223372036854775807]": {1.},
+	}
+	for expr, expected := range cases {
+		path, err := Compile(expr)
+		if assert.Nil(err, expr) {
+			assert.Equal(expected, path.Select(array), expr)
+		}
+	}
+
+	path, _ := Compile("$[::-9223372036854775808]")
+	assert.Empty(path.Select([]interface{}{}))
+}
+
+func TestJSONPathNumberModes(t *testing.T) {
+	assert := assert.New(t)
+	path, err := Compile(`$..book[?(@.price > 10 && @.price < 20)].title`)
+	assert.Nil(err)
+
+	for _, mode := range []NumberMode{NumberFloat64, NumberString, NumberInt64, NumberBig} {
+		tree, err := ParseOptions{Numbers: mode}.GogoJson(storeJSON)
+		assert.Nil(err)
+		assert.Equal([]interface{}{"Sword of Honour"}, path.Select(tree))
+	}
+}
+
+func TestJSONPathSyntaxErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := map[string]*PathSyntaxError{
+		`store`:                   {Msg: "Expected '$' at the start of the path", Column: 1},
+		`$.`:                      {Msg: "Unexpected end of path, expected a name or '*'", Column: 3},
+		`$.a b`:                   {Msg: "Unexpected ' '", Column: 4},
+		`$[1`:                     {Msg: "Unexpected end of path, expected ',' or ']'", Column: 4},
+		`$[]`:                     {Msg: "Unexpected ']', expected a selector", Column: 3},
+		`$['a\x']`:                {Msg: `Invalid escape sequence '\x'`, Column: 5},
+		`$["a]`:                   {Msg: "Unexpected end of path, expected closing \"", Column: 6},
+		`$[?(@.a = 1)]`:           {Msg: "Unexpected '=', expected ')'", Column: 9},
+		`$[?(1)]`:                 {Msg: "Expected a comparison or a path to test for existence", Column: 5},
+		`$[?(@.a < 1.)]`:          {Msg: "Invalid number '1.'", Column: 11},
+		`$[?(@.a == nil)]`:        {Msg: "Unexpected 'n', expected a value", Column: 12},
+		`$.ünï[x]`:                {Msg: "Unexpected 'x', expected a selector", Column: 7},
+		`$[99999999999999999999]`: {Msg: "Invalid integer '99999999999999999999'", Column: 3},
+	}
+	for expr, expected := range cases {
+		path, err := Compile(expr)
+		assert.Nil(path, expr)
+		assert.Equal(expected, err, expr)
+	}
+
+	_, err := Compile(`$[?(@.a ==`)
+	assert.EqualError(err, "Unexpected end of path, expected a value (column 11)")
+}
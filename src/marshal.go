@@ -0,0 +1,270 @@
+package gogojson
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// MarshalOptions controls the output of the serializer. The zero value
+// produces compact json, which is what Marshal returns
+type MarshalOptions struct {
+	// Prefix starts every line but the first when Indent is set
+	Prefix string
+	// Indent is repeated once per nesting level, no newlines are written
+	// when it is empty
+	Indent string
+	// SortKeys writes object keys in sorted order, which makes the output
+	// byte-stable across runs
+	SortKeys bool
+}
+
+type encoder struct {
+	buf   bytes.Buffer
+	opts  MarshalOptions
+	depth int
+	// nesting counts the pointers, maps and slices entered to detect cycles
+	nesting int
+}
+
+// Marshal serializes a value to compact json. Besides the generic values
+// GogoJson returns, any go value is supported that has a json representation:
+// structs, honoring json tags like Unmarshal, pointers, slices, arrays, maps
+// with string, integer or encoding.TextMarshaler keys, strings, numbers
+// including Number and math/big values, and booleans. Byte slices are
+// encoded as base64 strings. Types implementing Marshaler or
+// encoding.TextMarshaler encode themselves
+func Marshal(v interface{}) ([]byte, error) {
+	return MarshalOptions{}.Marshal(v)
+}
+
+// MarshalIndent works like Marshal, but puts every array element and object
+// entry on its own line, starting with prefix and indented by indent
+func MarshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
+	return MarshalOptions{Prefix: prefix, Indent: indent}.Marshal(v)
+}
+
+// Marshal serializes v according to the options
+func (opts MarshalOptions) Marshal(v interface{}) ([]byte, error) {
+	enc := &encoder{opts: opts}
+	if err := enc.encode(v); err != nil {
+		return nil, atPath(err, "$")
+	}
+
+	return enc.buf.Bytes(), nil
+}
+
+func (enc *encoder) encode(v interface{}) error {
+	switch value := v.(type) {
+	case nil:
+		enc.buf.WriteString("null")
+	case bool:
+		enc.buf.WriteString(strconv.FormatBool(value))
+	case string:
+		enc.encodeString(value)
+	case float64:
+		return enc.encodeFloat(value, 64)
+	case float32:
+		return enc.encodeFloat(float64(value), 32)
+	case int:
+		enc.buf.WriteString(strconv.FormatInt(int64(value), 10))
+	case int8:
+		enc.buf.WriteString(strconv.FormatInt(int64(value), 10))
+	case int16:
+		enc.buf.WriteString(strconv.FormatInt(int64(value), 10))
+	case int32:
+		enc.buf.WriteString(strconv.FormatInt(int64(value), 10))
+	case int64:
+		enc.buf.WriteString(strconv.FormatInt(value, 10))
+	case uint:
+		enc.buf.WriteString(strconv.FormatUint(uint64(value), 10))
+	case uint8:
+		enc.buf.WriteString(strconv.FormatUint(uint64(value), 10))
+	case uint16:
+		enc.buf.WriteString(strconv.FormatUint(uint64(value), 10))
+	case uint32:
+		enc.buf.WriteString(strconv.FormatUint(uint64(value), 10))
+	case uint64:
+		enc.buf.WriteString(strconv.FormatUint(value, 10))
+	case Number:
+		if value == "" {
+			// the zero Number, e.g. of an unset struct field
+			value = "0"
+		}
+		if !isNumberLiteral(string(value)) {
+			return &UnsupportedValueError{Value: fmt.Sprintf("Number(%q)", string(value))}
+		}
+		enc.buf.WriteString(string(value))
+	case *big.Int:
+		enc.buf.WriteString(value.String())
+	case *big.Float:
+		if value.IsInf() {
+			return &UnsupportedValueError{Value: value.String()}
+		}
+		enc.buf.WriteString(value.Text('g', -1))
+	case map[string]interface{}:
+		return enc.encodeMap(value)
+	case []interface{}:
+		return enc.encodeArray(value)
+	default:
+		rv := reflect.ValueOf(v)
+		return typeEncoder(rv.Type())(enc, rv)
+	}
+
+	return nil
+}
+
+func (enc *encoder) encodeMap(m map[string]interface{}) error {
+	if err := enc.enter(); err != nil {
+		return err
+	}
+	defer enc.leave()
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	if enc.opts.SortKeys {
+		sort.Strings(keys)
+	}
+
+	enc.open('{', len(keys))
+	for i, key := range keys {
+		enc.separate(i)
+		enc.encodeString(key)
+		enc.colon()
+		if err := enc.encode(m[key]); err != nil {
+			return atPath(err, pathKey("", key))
+		}
+	}
+	enc.close('}', len(keys))
+
+	return nil
+}
+
+func (enc *encoder) encodeArray(a []interface{}) error {
+	if err := enc.enter(); err != nil {
+		return err
+	}
+	defer enc.leave()
+
+	enc.open('[', len(a))
+	for i, value := range a {
+		enc.separate(i)
+		if err := enc.encode(value); err != nil {
+			return atPath(err, pathIndex("", i))
+		}
+	}
+	enc.close(']', len(a))
+
+	return nil
+}
+
+// open, separate and close write the punctuation of objects and arrays,
+// including the whitespace needed for indented output
+func (enc *encoder) open(bracket byte, length int) {
+	enc.buf.WriteByte(bracket)
+	if length > 0 {
+		enc.depth++
+	}
+}
+
+func (enc *encoder) separate(index int) {
+	if index > 0 {
+		enc.buf.WriteByte(',')
+	}
+	enc.newline()
+}
+
+func (enc *encoder) colon() {
+	enc.buf.WriteByte(':')
+	if enc.opts.Indent != "" {
+		enc.buf.WriteByte(' ')
+	}
+}
+
+func (enc *encoder) close(bracket byte, length int) {
+	if length > 0 {
+		enc.depth--
+		enc.newline()
+	}
+	enc.buf.WriteByte(bracket)
+}
+
+func (enc *encoder) newline() {
+	if enc.opts.Indent == "" {
+		return
+	}
+
+	enc.buf.WriteByte('\n')
+	enc.buf.WriteString(enc.opts.Prefix)
+	for i := 0; i < enc.depth; i++ {
+		enc.buf.WriteString(enc.opts.Indent)
+	}
+}
+
+func (enc *encoder) encodeFloat(f float64, bits int) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return &UnsupportedValueError{Value: strconv.FormatFloat(f, 'g', -1, bits)}
+	}
+
+	// only use the exponent format for very small or very large numbers
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	enc.buf.WriteString(strconv.FormatFloat(f, format, -1, bits))
+
+	return nil
+}
+
+const hex = "0123456789abcdef"
+
+func (enc *encoder) encodeString(s string) {
+	enc.buf.WriteByte('"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch c {
+			case '"', '\\':
+				enc.buf.WriteByte('\\')
+				enc.buf.WriteByte(c)
+			case '\n':
+				enc.buf.WriteString(`\n`)
+			case '\r':
+				enc.buf.WriteString(`\r`)
+			case '\t':
+				enc.buf.WriteString(`\t`)
+			case '\b':
+				enc.buf.WriteString(`\b`)
+			case '\f':
+				enc.buf.WriteString(`\f`)
+			default:
+				if c < 0x20 {
+					enc.buf.WriteString(`\u00`)
+					enc.buf.WriteByte(hex[c>>4])
+					enc.buf.WriteByte(hex[c&0xf])
+				} else {
+					enc.buf.WriteByte(c)
+				}
+			}
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			// invalid utf-8 is replaced instead of producing invalid json
+			enc.buf.WriteString(`�`)
+		} else {
+			enc.buf.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	enc.buf.WriteByte('"')
+}
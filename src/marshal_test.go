@@ -0,0 +1,138 @@
+package gogojson
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// roundTrip feeds the output of Marshal back through Tokenize and Parse
+func roundTrip(t *testing.T, v map[string]interface{}) map[string]interface{} {
+	serialized, err := Marshal(v)
+	assert.Nil(t, err)
+
+	tokens, err := Tokenize(MakeIterator(string(serialized)))
+	assert.Nil(t, err, string(serialized))
+
+	parsed, err := Parse(tokens)
+	assert.Nil(t, err, string(serialized))
+
+	return parsed
+}
+
+func TestMarshal(t *testing.T) {
+	assert := assert.New(t)
+	values := map[string]interface{}{
+		`null`:                nil,
+		`true`:                true,
+		`"hello"`:             "hello",
+		`42`:                  float64(42),
+		`-0.5`:                -0.5,
+		`1e+21`:               1e21,
+		`1e-07`:               1e-7,
+		`7`:                   7,
+		`[]`:                  []interface{}{},
+		`[1,"two",null]`:      []interface{}{1, "two", nil},
+		`{}`:                  map[string]interface{}{},
+		`{"a":[{"b":false}]}`: map[string]interface{}{"a": []interface{}{map[string]interface{}{"b": false}}},
+	}
+
+	for expected, value := range values {
+		serialized, err := Marshal(value)
+
+		assert.Nil(err, expected)
+		assert.Equal(expected, string(serialized))
+	}
+}
+
+func TestMarshalEscape(t *testing.T) {
+	assert := assert.New(t)
+	serialized, err := Marshal("quote \" backslash \\ \n\r\t\b\f \x01 é \xff")
+
+	assert.Nil(err)
+	assert.Equal(`"quote \" backslash \\ \n\r\t\b\f \u0001 é �"`, string(serialized))
+}
+
+func TestMarshalErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := Marshal(map[string]interface{}{"a": make(chan int)})
+	assert.Equal(&UnsupportedTypeError{Type: "chan int"}, err)
+
+	_, err = Marshal(map[string]interface{}{"a": []func(){nil}})
+	assert.Equal(&UnsupportedTypeError{Type: "func()"}, err)
+
+	_, err = Marshal([]interface{}{math.NaN()})
+	assert.Equal(&UnsupportedValueError{Value: "NaN"}, err)
+
+	_, err = Marshal(math.Inf(-1))
+	assert.Equal(&UnsupportedValueError{Value: "-Inf"}, err)
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	documents := []map[string]interface{}{
+		{},
+		{"name": "Peter", "age": float64(42), "male": true, "secrets": nil},
+		{"config": map[string]interface{}{"surname": "Griffin", "nested": map[string]interface{}{}}},
+		{"kids": []interface{}{"Meg", "Chris", map[string]interface{}{"name": "Stewie"}, []interface{}{}}},
+		{"umlauts": "äöü", "emoji": "😀", "ключ": "значение"},
+		{"fraction": 0.1, "negative": -2.5, "big": float64(123456789), "tiny": 1e-9, "huge": 1e300},
+		{"escapes": "quote \" backslash \\ /\n\r\t\b\f \x01", "key \"with\" quotes": true},
+	}
+
+	for _, document := range documents {
+		assert.Equal(t, document, roundTrip(t, document))
+	}
+
+	// parsed input should survive a second pass unchanged as well
+	parsed, err := GogoJson(parseJSON)
+	assert.Nil(t, err)
+	assert.Equal(t, parsed, roundTrip(t, parsed.(map[string]interface{})))
+}
+
+var indentJSON = `{
+#  "a": [
+#    1,
+#    {
+#      "b": null
+#    }
+#  ],
+#  "c": {},
+#  "d": []
+#}`
+
+func TestMarshalIndent(t *testing.T) {
+	assert := assert.New(t)
+	value := map[string]interface{}{
+		"d": []interface{}{},
+		"a": []interface{}{1, map[string]interface{}{"b": nil}},
+		"c": map[string]interface{}{},
+	}
+
+	serialized, err := MarshalOptions{Prefix: "#", Indent: "  ", SortKeys: true}.Marshal(value)
+	assert.Nil(err)
+	assert.Equal(indentJSON, string(serialized))
+
+	serialized, err = MarshalIndent([]interface{}{"x"}, "", "  ")
+	assert.Nil(err)
+	assert.Equal("[\n  \"x\"\n]", string(serialized))
+
+	serialized, err = MarshalIndent(true, "", "  ")
+	assert.Nil(err)
+	assert.Equal("true", string(serialized))
+}
+
+func TestMarshalSortKeys(t *testing.T) {
+	assert := assert.New(t)
+	parsed, err := GogoJson(`{"z": 1, "y": {"b": 2, "a": 1}, "x": [{"d": 1, "c": 2}]}`)
+	assert.Nil(err)
+
+	opts := MarshalOptions{SortKeys: true}
+	for i := 0; i < 10; i++ {
+		serialized, err := opts.Marshal(parsed)
+
+		assert.Nil(err)
+		assert.Equal(`{"x":[{"c":2,"d":1}],"y":{"a":1,"b":2},"z":1}`, string(serialized))
+	}
+}
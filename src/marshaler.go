@@ -0,0 +1,206 @@
+package gogojson
+
+import (
+	"encoding"
+	"reflect"
+	"strconv"
+)
+
+// Marshaler is implemented by types that encode themselves to json. The
+// method has the same signature as encoding/json.Marshaler, so types written
+// for the standard library work unchanged. The output is validated and
+// reformatted to fit the surrounding document
+type Marshaler interface {
+	MarshalJSON() ([]byte, error)
+}
+
+// Unmarshaler is implemented by types that decode themselves from json, with
+// the same signature as encoding/json.Unmarshaler. Since Unmarshal decodes a
+// parsed document, the method receives the value re-encoded as compact json
+// with sorted object keys rather than the original input
+type Unmarshaler interface {
+	UnmarshalJSON([]byte) error
+}
+
+var (
+	marshalerType       = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType     = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// newMarshalerEncoder returns an encoder for types implementing Marshaler or
+// encoding.TextMarshaler, or nil for all other types. Methods with pointer
+// receivers are only used for addressable values
+func newMarshalerEncoder(t reflect.Type) encoderFunc {
+	switch {
+	case t.Implements(marshalerType):
+		return marshalerEncoder
+	case t.Implements(textMarshalerType):
+		return textMarshalerEncoder
+	case t.Kind() == reflect.Ptr:
+		return nil
+	case reflect.PtrTo(t).Implements(marshalerType):
+		return condAddrEncoder(marshalerEncoder, newKindEncoder(t))
+	case reflect.PtrTo(t).Implements(textMarshalerType):
+		return condAddrEncoder(textMarshalerEncoder, newKindEncoder(t))
+	}
+
+	return nil
+}
+
+// condAddrEncoder passes the address of addressable values to addrEncoder
+// and all other values to fallback
+func condAddrEncoder(addrEncoder, fallback encoderFunc) encoderFunc {
+	return func(enc *encoder, v reflect.Value) error {
+		if v.CanAddr() {
+			return addrEncoder(enc, v.Addr())
+		}
+
+		return fallback(enc, v)
+	}
+}
+
+func marshalerEncoder(enc *encoder, v reflect.Value) error {
+	if isNilReference(v) {
+		enc.buf.WriteString("null")
+		return nil
+	}
+
+	raw, err := v.Interface().(Marshaler).MarshalJSON()
+	if err == nil {
+		err = enc.writeJSON(raw)
+	}
+	if err != nil {
+		return &MarshalerError{Type: v.Type(), Err: err}
+	}
+
+	return nil
+}
+
+func textMarshalerEncoder(enc *encoder, v reflect.Value) error {
+	if isNilReference(v) {
+		enc.buf.WriteString("null")
+		return nil
+	}
+
+	text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return &MarshalerError{Type: v.Type(), Err: err}
+	}
+	enc.encodeString(string(text))
+
+	return nil
+}
+
+func isNilReference(v reflect.Value) bool {
+	return (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil()
+}
+
+// writeJSON validates the output of a Marshaler and writes it with the
+// layout of the surrounding document
+func (enc *encoder) writeJSON(raw []byte) error {
+	tokens, err := Tokenize(MakeIterator(string(raw)))
+	if err != nil {
+		return err
+	}
+	if _, err := (ParseOptions{Numbers: NumberString}).parseDocument(&sliceSource{tokens}); err != nil {
+		return err
+	}
+
+	for i, token := range tokens {
+		switch token.Type {
+		case PUNC:
+			punc := token.Value.(string)
+			switch punc {
+			case "{", "[":
+				if isClosingToken(tokens[i+1]) {
+					enc.open(punc[0], 0)
+				} else {
+					enc.open(punc[0], 1)
+					enc.separate(0)
+				}
+			case "}", "]":
+				if isOpeningToken(tokens[i-1]) {
+					enc.close(punc[0], 0)
+				} else {
+					enc.close(punc[0], 1)
+				}
+			case ",":
+				enc.separate(1)
+			case ":":
+				enc.colon()
+			}
+		case STRING:
+			enc.encodeString(token.Value.(string))
+		case NUM:
+			enc.buf.WriteString(token.Raw)
+		case BOOL:
+			enc.buf.WriteString(strconv.FormatBool(token.Value.(bool)))
+		case NULL:
+			enc.buf.WriteString("null")
+		}
+	}
+
+	return nil
+}
+
+func isOpeningToken(token *Token) bool {
+	return token.Type == PUNC && (token.Value == "{" || token.Value == "[")
+}
+
+func isClosingToken(token *Token) bool {
+	return token.Type == PUNC && (token.Value == "}" || token.Value == "]")
+}
+
+// atPath prefixes the path of a MarshalerError with the location of the
+// value that contains the failing one, while the error travels up
+func atPath(err error, location string) error {
+	if marshalerErr, ok := err.(*MarshalerError); ok {
+		marshalerErr.Path = location + marshalerErr.Path
+	}
+
+	return err
+}
+
+// unmarshalers returns the Unmarshaler or encoding.TextUnmarshaler that is
+// implemented by the address of dst, if any
+func unmarshalers(dst reflect.Value) (Unmarshaler, encoding.TextUnmarshaler) {
+	if dst.Kind() == reflect.Ptr || dst.Kind() == reflect.Interface || !dst.CanAddr() {
+		return nil, nil
+	}
+
+	ptr := dst.Addr()
+	if ptr.Type().Implements(unmarshalerType) {
+		return ptr.Interface().(Unmarshaler), nil
+	}
+	if ptr.Type().Implements(textUnmarshalerType) {
+		return nil, ptr.Interface().(encoding.TextUnmarshaler)
+	}
+
+	return nil, nil
+}
+
+func (dec *decoder) callUnmarshaler(path string, src interface{}, dst reflect.Value, u Unmarshaler) error {
+	raw, err := MarshalOptions{SortKeys: true}.Marshal(src)
+	if err == nil {
+		err = u.UnmarshalJSON(raw)
+	}
+	if err != nil {
+		return &UnmarshalerError{Type: dst.Type(), Path: path, Err: err}
+	}
+
+	return nil
+}
+
+func (dec *decoder) callTextUnmarshaler(path string, src interface{}, dst reflect.Value, u encoding.TextUnmarshaler) error {
+	text, ok := src.(string)
+	if !ok {
+		return typeError(path, src, dst.Type())
+	}
+
+	if err := u.UnmarshalText([]byte(text)); err != nil {
+		return &UnmarshalerError{Type: dst.Type(), Path: path, Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package gogojson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// types written for encoding/json satisfy the interfaces of this package
+var (
+	_ Marshaler   = json.Marshaler(nil)
+	_ Unmarshaler = json.Unmarshaler(nil)
+)
+
+// Money is encoded as an object of amount and currency
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	if m.Currency == "" {
+		return nil, errors.New("missing currency")
+	}
+	return []byte(fmt.Sprintf(`{"amount": "%d.%02d", "currency": "%s"}`, m.Cents/100, m.Cents%100, m.Currency)), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var wire struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}
+	if err := Unmarshal(data, &wire); err != nil {
+		return err
+	}
+
+	var units, cents int64
+	if _, err := fmt.Sscanf(wire.Amount, "%d.%d", &units, &cents); err != nil {
+		return err
+	}
+	m.Cents, m.Currency = units*100+cents, wire.Currency
+	return nil
+}
+
+// Level is encoded as its name
+type Level int
+
+var levels = []string{"debug", "info", "error"}
+
+func (l Level) MarshalText() ([]byte, error) {
+	if int(l) >= len(levels) {
+		return nil, fmt.Errorf("unknown level %d", int(l))
+	}
+	return []byte(levels[l]), nil
+}
+
+func (l *Level) UnmarshalText(text []byte) error {
+	for i, name := range levels {
+		if name == string(text) {
+			*l = Level(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown level %q", text)
+}
+
+// Timeout only has a pointer receiver, so it is used for addressable values
+type Timeout time.Duration
+
+func (t *Timeout) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + time.Duration(*t).String() + `"`), nil
+}
+
+type invalidMarshaler struct{}
+
+func (invalidMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`{"open": [`), nil
+}
+
+type Alert struct {
+	Price   Money           `json:"price"`
+	Limit   *Money          `json:"limit"`
+	Level   Level           `json:"level"`
+	ByLevel map[Level]int   `json:"by_level"`
+	Timeout Timeout         `json:"timeout"`
+	Raw     json.RawMessage `json:"raw"`
+	At      time.Time       `json:"at"`
+}
+
+func TestMarshalerRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	alert := Alert{
+		Price:   Money{Cents: 1250, Currency: "EUR"},
+		Level:   2,
+		ByLevel: map[Level]int{0: 1},
+		Timeout: Timeout(90 * time.Second),
+		Raw:     json.RawMessage(`[1, {"b": true}]`),
+		At:      time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	out, err := Marshal(&alert)
+	assert.Nil(err)
+	assert.Equal(`{"price":{"amount":"12.50","currency":"EUR"},"limit":null,"level":"error",`+
+		`"by_level":{"debug":1},"timeout":"1m30s","raw":[1,{"b":true}],"at":"2020-01-01T12:00:00Z"}`, string(out))
+
+	// without an address the pointer receiver of Timeout can't be used
+	out, err = Marshal(alert)
+	assert.Nil(err)
+	assert.Contains(string(out), `"timeout":90000000000,`)
+
+	var decoded Alert
+	assert.Nil(Unmarshal([]byte(`{"price": {"currency": "USD", "amount": "3.05"}, "limit": {"amount": "1.00",
+		"currency": "USD"}, "level": "info", "by_level": {"error": 2}, "raw": {"z": 1, "a": [2]},
+		"at": "2021-06-01T00:00:00Z"}`), &decoded))
+	assert.Equal(Money{Cents: 305, Currency: "USD"}, decoded.Price)
+	assert.Equal(&Money{Cents: 100, Currency: "USD"}, decoded.Limit)
+	assert.Equal(Level(1), decoded.Level)
+	assert.Equal(map[Level]int{2: 2}, decoded.ByLevel)
+	assert.Equal(`{"a":[2],"z":1}`, string(decoded.Raw))
+	assert.Equal(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), decoded.At)
+}
+
+func TestMarshalerIndent(t *testing.T) {
+	assert := assert.New(t)
+
+	out, err := MarshalIndent([]interface{}{Money{Cents: 5, Currency: "EUR"}, json.RawMessage(`{"a":[],"b":{}}`)}, "", "  ")
+	assert.Nil(err)
+	assert.Equal(strings.Join([]string{
+		`[`,
+		`  {`,
+		`    "amount": "0.05",`,
+		`    "currency": "EUR"`,
+		`  },`,
+		`  {`,
+		`    "a": [],`,
+		`    "b": {}`,
+		`  }`,
+		`]`,
+	}, "\n"), string(out))
+}
+
+func TestMarshalerErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := Marshal(map[string]interface{}{"items": []interface{}{1, Money{}}})
+	assert.EqualError(err, "Cannot encode go value of type gogojson.Money at $.items[1]: missing currency")
+
+	_, err = Marshal(Alert{Price: Money{Currency: "EUR"}, Level: 7})
+	assert.EqualError(err, "Cannot encode go value of type gogojson.Level at $.level: unknown level 7")
+
+	_, err = Marshal(struct{ Bad invalidMarshaler }{})
+	var marshalerErr *MarshalerError
+	assert.True(errors.As(err, &marshalerErr))
+	assert.Equal("$.Bad", marshalerErr.Path)
+	assert.IsType(&SyntaxError{}, errors.Unwrap(err))
+
+	var alert Alert
+	err = Unmarshal([]byte(`{"by_level": {"fatal": 1}}`), &alert)
+	assert.EqualError(err, `Cannot decode into go value of type gogojson.Level at $.by_level.fatal: unknown level "fatal"`)
+
+	err = Unmarshal([]byte(`{"level": 1}`), &alert)
+	assert.EqualError(err, "Cannot decode number 1 into go value of type gogojson.Level at $.level")
+
+	err = Unmarshal([]byte(`[{"price": {"amount": "x"}}]`), &[]Alert{})
+	var unmarshalerErr *UnmarshalerError
+	assert.True(errors.As(err, &unmarshalerErr))
+	assert.Equal("$[0].price", unmarshalerErr.Path)
+	assert.Equal("Money", unmarshalerErr.Type.Name())
+}
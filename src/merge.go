@@ -0,0 +1,71 @@
+package gogojson
+
+// MergePatch applies a JSON merge patch as defined by RFC 7386 to a parsed
+// tree and returns the result. Object members of the patch are merged into
+// target recursively, null members delete the member, and any other patch
+// value replaces target as a whole. target itself is never changed
+func MergePatch(target, patch interface{}) interface{} {
+	members, ok := patch.(map[string]interface{})
+	if !ok {
+		return deepCopy(patch)
+	}
+
+	result, ok := deepCopy(target).(map[string]interface{})
+	if !ok {
+		result = make(map[string]interface{}, len(members))
+	}
+	for key, value := range members {
+		if value == nil {
+			delete(result, key)
+		} else {
+			result[key] = MergePatch(result[key], value)
+		}
+	}
+
+	return result
+}
+
+// CreateMergePatch returns a merge patch that turns original into modified.
+// Since null deletes members, a patch can't set one to null: such changes
+// are reported as a *PointerError naming the member
+func CreateMergePatch(original, modified interface{}) (interface{}, error) {
+	return createMergePatch(Pointer{}, original, modified)
+}
+
+func createMergePatch(path Pointer, original, modified interface{}) (interface{}, error) {
+	modifiedMembers, ok := modified.(map[string]interface{})
+	if !ok {
+		return deepCopy(modified), nil
+	}
+	originalMembers, ok := original.(map[string]interface{})
+	if !ok {
+		// the patch has to create the object from scratch
+		originalMembers = map[string]interface{}{}
+	}
+
+	patch := map[string]interface{}{}
+	for key := range originalMembers {
+		if _, ok := modifiedMembers[key]; !ok {
+			patch[key] = nil
+		}
+	}
+
+	for _, key := range sortedKeys(modifiedMembers) {
+		value := modifiedMembers[key]
+		previous, existed := originalMembers[key]
+		if existed && jsonEqual(previous, value) {
+			continue
+		}
+		if value == nil {
+			return nil, childPointer(path, key).errorf("Merge patches can't set a member to null")
+		}
+
+		change, err := createMergePatch(childPointer(path, key), previous, value)
+		if err != nil {
+			return nil, err
+		}
+		patch[key] = change
+	}
+
+	return patch, nil
+}
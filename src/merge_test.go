@@ -0,0 +1,123 @@
+package gogojson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePatch(t *testing.T) {
+	assert := assert.New(t)
+
+	// examples of RFC 7386, appendix A
+	cases := []struct{ target, patch, expected string }{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, c := range cases {
+		target, _ := GogoJson(c.target)
+		patch, _ := GogoJson(c.patch)
+		expected, _ := GogoJson(c.expected)
+		assert.Equal(expected, MergePatch(target, patch), c.target+" + "+c.patch)
+	}
+}
+
+func TestMergePatchCopies(t *testing.T) {
+	assert := assert.New(t)
+	target, _ := GogoJson(`{"a": {"b": [1]}, "c": 2}`)
+	original, _ := GogoJson(`{"a": {"b": [1]}, "c": 2}`)
+	patch, _ := GogoJson(`{"a": {"d": {"e": true}}, "c": null}`)
+
+	result := MergePatch(target, patch).(map[string]interface{})
+	assert.Equal(original, target)
+
+	result["a"].(map[string]interface{})["d"].(map[string]interface{})["e"] = false
+	assert.Equal(true, patch.(map[string]interface{})["a"].(map[string]interface{})["d"].(map[string]interface{})["e"])
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct{ original, modified, expected string }{
+		{`{"a": 1, "b": {"c": [1], "d": 2}}`, `{"a": 1, "b": {"c": [1], "d": 2}}`, `{}`},
+		{`{"a": 1, "b": 2}`, `{"b": 3, "c": 4}`, `{"a": null, "b": 3, "c": 4}`},
+		{`{"a": {"b": {"c": 1, "d": 2}}, "e": [1]}`, `{"a": {"b": {"c": 1}}, "e": [1, 2]}`, `{"a": {"b": {"d": null}}, "e": [1, 2]}`},
+		{`{"a": 1}`, `{"a": {"b": true}}`, `{"a": {"b": true}}`},
+		{`{"a": {"b": true}}`, `{"a": []}`, `{"a": []}`},
+		{`[1]`, `{"a": 1}`, `{"a": 1}`},
+		{`{"a": 1}`, `[1]`, `[1]`},
+		{`{"a": null}`, `{"a": null, "b": 1}`, `{"b": 1}`},
+	}
+	for _, c := range cases {
+		original, _ := GogoJson(c.original)
+		modified, _ := GogoJson(c.modified)
+		expected, _ := GogoJson(c.expected)
+
+		patch, err := CreateMergePatch(original, modified)
+		assert.Nil(err, c.modified)
+		assert.Equal(expected, patch, c.modified)
+		assert.Equal(modified, MergePatch(original, patch), c.modified)
+	}
+
+	// numbers are compared by value, not by representation
+	original, _ := ParseOptions{Numbers: NumberString}.GogoJson(`{"a": 1.0}`)
+	modified, _ := GogoJson(`{"a": 1}`)
+	patch, err := CreateMergePatch(original, modified)
+	assert.Nil(err)
+	assert.Equal(map[string]interface{}{}, patch)
+
+	errors := map[string]string{
+		`{"a": null}`:                `Merge patches can't set a member to null (pointer "/a")`,
+		`{"a": 1, "b": {"c": null}}`: `Merge patches can't set a member to null (pointer "/b/c")`,
+	}
+	for modified, expected := range errors {
+		tree, _ := GogoJson(modified)
+		_, err := CreateMergePatch(map[string]interface{}{"a": 1.0}, tree)
+		assert.EqualError(err, expected, modified)
+	}
+}
+
+func TestMergePatchMarshal(t *testing.T) {
+	assert := assert.New(t)
+	original, _ := GogoJson(`{"name": "config", "limits": {"cpu": 1, "memory": 512}, "debug": true}`)
+	modified, _ := GogoJson(`{"name": "config", "limits": {"cpu": 2, "memory": 512}, "tags": ["a"]}`)
+
+	patch, err := CreateMergePatch(original, modified)
+	assert.Nil(err)
+	out, err := MarshalOptions{SortKeys: true}.Marshal(patch)
+	assert.Nil(err)
+	assert.Equal(`{"debug":null,"limits":{"cpu":2},"tags":["a"]}`, string(out))
+
+	parsed, err := GogoJson(string(out))
+	assert.Nil(err)
+	assert.Equal(modified, MergePatch(original, parsed))
+}
+
+func TestCreateMergePatchExactNumbers(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, mode := range []NumberMode{NumberString, NumberInt64, NumberBig} {
+		opts := ParseOptions{Numbers: mode}
+		original, _ := opts.GogoJson(`{"id": 9007199254740993, "ref": {"id": 9007199254740993}}`)
+		modified, _ := opts.GogoJson(`{"id": 9007199254740992, "ref": {"id": 9007199254740993}}`)
+
+		patch, err := CreateMergePatch(original, modified)
+		assert.Nil(err)
+		expected, _ := opts.GogoJson(`{"id": 9007199254740992}`)
+		assert.Equal(expected, patch, mode)
+		assert.Equal(modified, MergePatch(original, patch))
+	}
+}
@@ -0,0 +1,146 @@
+package gogojson
+
+import (
+	"io"
+)
+
+// NDJSONOptions controls how NDJSONReader reads records
+type NDJSONOptions struct {
+	ParseOptions
+	// SkipInvalid drops records that aren't valid json instead of failing,
+	// their errors are available through Skipped
+	SkipInvalid bool
+}
+
+// NDJSONReader reads newline delimited json, where every line holds one
+// document. Blank lines are ignored
+type NDJSONReader struct {
+	iter    Iterator
+	opts    NDJSONOptions
+	record  int
+	skipped []*RecordError
+}
+
+// NewNDJSONReader returns a reader for the records of source
+func NewNDJSONReader(source Iterator) *NDJSONReader {
+	return NDJSONOptions{}.NewNDJSONReader(source)
+}
+
+// NewNDJSONReader works like the package level NewNDJSONReader, but applies
+// the options
+func (opts NDJSONOptions) NewNDJSONReader(source Iterator) *NDJSONReader {
+	return &NDJSONReader{iter: source, opts: opts}
+}
+
+// Next parses the next record. Errors are returned as *RecordError and
+// io.EOF once all records are read
+func (r *NDJSONReader) Next() (interface{}, error) {
+	for {
+		start := r.iter.GetPosition()
+		if r.iter.Eof() {
+			if err := r.iter.Err(); err != nil {
+				return nil, &RecordError{Record: r.record, Line: start.Line, Err: err}
+			}
+			return nil, io.EOF
+		}
+
+		// records are parsed straight from the source, so its options and
+		// positions apply
+		line := lineIterator{r.iter}
+		lexer{line}.skipWhitespace()
+		var value interface{}
+		var err error
+		blank := line.Eof()
+		if !blank {
+			value, err = r.opts.parseDocument(&lexerSource{lexer: lexer{line}})
+		}
+
+		// the rest of a bad record is dropped along with the newline
+		for !line.Eof() {
+			line.Next()
+		}
+		r.iter.Next()
+		if readErr := r.iter.Err(); readErr != nil {
+			// the input can't be read any further, so there is nothing to skip
+			return nil, &RecordError{Record: r.record, Line: start.Line, Err: readErr}
+		} else if blank {
+			continue
+		}
+
+		record := r.record
+		r.record++
+		if err == nil {
+			return value, nil
+		}
+
+		recordErr := &RecordError{Record: record, Line: start.Line, Err: err}
+		if !r.opts.SkipInvalid {
+			return nil, recordErr
+		}
+		r.skipped = append(r.skipped, recordErr)
+	}
+}
+
+// Skipped returns the errors of all records dropped so far because of
+// SkipInvalid
+func (r *NDJSONReader) Skipped() []*RecordError {
+	return r.skipped
+}
+
+// lineIterator ends the input of an Iterator at the next newline, which is
+// left unread
+type lineIterator struct {
+	Iterator
+}
+
+func (iter lineIterator) Next() string {
+	if iter.Eof() {
+		return ""
+	}
+
+	return iter.Iterator.Next()
+}
+
+func (iter lineIterator) Peek() string {
+	if char := iter.Iterator.Peek(); char != "\n" {
+		return char
+	}
+
+	return ""
+}
+
+func (iter lineIterator) Eof() bool {
+	return iter.Peek() == ""
+}
+
+// NDJSONWriter writes values as newline delimited json
+type NDJSONWriter struct {
+	writer io.Writer
+	opts   MarshalOptions
+}
+
+// NewNDJSONWriter returns a writer for compact records to w
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	return MarshalOptions{}.NewNDJSONWriter(w)
+}
+
+// NewNDJSONWriter works like the package level NewNDJSONWriter, but applies
+// the options. Prefix and Indent are ignored, as every record has to stay on
+// its own line
+func (opts MarshalOptions) NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	opts.Prefix = ""
+	opts.Indent = ""
+
+	return &NDJSONWriter{writer: w, opts: opts}
+}
+
+// Write serializes v as a single record
+func (w *NDJSONWriter) Write(v interface{}) error {
+	serialized, err := w.opts.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.writer.Write(append(serialized, '\n'))
+	return err
+}
@@ -0,0 +1,150 @@
+package gogojson
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const logLines = `{"level": "info", "msg": "started"}
+
+{"level": "warn", "msg": "slow", "ms": 1200}
+{"level": "error", "msg": "oops
+[1, 2, 3]
+`
+
+func TestNDJSONReader(t *testing.T) {
+	assert := assert.New(t)
+	reader := NewNDJSONReader(MakeIterator(`{"a": 1}
+[true]
+
+"x"`))
+
+	records := []interface{}{}
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(err)
+		records = append(records, record)
+	}
+
+	assert.Equal([]interface{}{map[string]interface{}{"a": float64(1)}, []interface{}{true}, "x"}, records)
+}
+
+func TestNDJSONReaderError(t *testing.T) {
+	assert := assert.New(t)
+	reader := NewNDJSONReader(MakeReaderIterator(strings.NewReader(logLines)))
+
+	_, err := reader.Next()
+	assert.Nil(err)
+	_, err = reader.Next()
+	assert.Nil(err)
+
+	_, err = reader.Next()
+	assert.Equal(&RecordError{
+		Record: 2,
+		Line:   4,
+		Err: &SyntaxError{
+			Msg:      "Unterminated string",
+			Position: Position{Line: 4, Column: 32, Offset: 113},
+			Pointer:  Pointer{"msg"},
+		},
+	}, err)
+	assert.Equal("record 2 (line 4): Unterminated string (line 4, column 32, offset 113)", err.Error())
+
+	// the reader can carry on after a bad record
+	record, err := reader.Next()
+	assert.Nil(err)
+	assert.Equal([]interface{}{float64(1), float64(2), float64(3)}, record)
+}
+
+func TestNDJSONReaderSkipInvalid(t *testing.T) {
+	assert := assert.New(t)
+	reader := NDJSONOptions{SkipInvalid: true, ParseOptions: ParseOptions{Numbers: NumberInt64}}.NewNDJSONReader(MakeIterator(logLines))
+
+	records := []interface{}{}
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(err)
+		records = append(records, record)
+	}
+
+	assert.Len(records, 3)
+	assert.Equal(int64(1200), records[1].(map[string]interface{})["ms"])
+	assert.Len(reader.Skipped(), 1)
+	assert.Equal(2, reader.Skipped()[0].Record)
+	assert.Equal(uint64(4), reader.Skipped()[0].Line)
+}
+
+func TestNDJSONReaderIteratorOptions(t *testing.T) {
+	assert := assert.New(t)
+	source := "{\"a\": \"\xff\"}\n{\"\u00e9\xff\": 1, \"b\": tru}\n"
+	iter := IteratorOptions{ReplaceInvalidUTF8: true, ByteColumns: true}.MakeReaderIterator(strings.NewReader(source))
+	reader := NewNDJSONReader(iter)
+
+	record, err := reader.Next()
+	assert.Nil(err)
+	assert.Equal(map[string]interface{}{"a": "\uFFFD"}, record)
+
+	// columns count bytes of the source, not of the replacement characters
+	_, err = reader.Next()
+	assert.Equal(&RecordError{
+		Record: 1,
+		Line:   2,
+		Err: &SyntaxError{
+			Msg:      "Invalid literal 'tru', expected true, false or null",
+			Position: Position{Line: 2, Column: 17, Offset: 27},
+			Pointer:  Pointer{"b"},
+		},
+	}, err)
+
+	_, err = reader.Next()
+	assert.Equal(io.EOF, err)
+}
+
+func TestNDJSONReaderReadError(t *testing.T) {
+	assert := assert.New(t)
+	failure := errors.New("broken pipe")
+	source := io.MultiReader(strings.NewReader("1\n2\n3"), iotest.ErrReader(failure))
+	reader := NDJSONOptions{SkipInvalid: true}.NewNDJSONReader(MakeReaderIterator(source))
+
+	reader.Next()
+	reader.Next()
+	_, err := reader.Next()
+
+	assert.Equal(&RecordError{Record: 2, Line: 3, Err: failure}, err)
+	assert.True(errors.Is(err, failure))
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	assert := assert.New(t)
+	var buf bytes.Buffer
+	writer := MarshalOptions{Indent: "  ", SortKeys: true}.NewNDJSONWriter(&buf)
+
+	assert.Nil(writer.Write(map[string]interface{}{"b": 2, "a": []interface{}{1}}))
+	assert.Nil(writer.Write("line\nbreak"))
+	assert.NotNil(writer.Write(map[string]interface{}{"bad": make(chan int)}))
+
+	assert.Equal("{\"a\":[1],\"b\":2}\n\"line\\nbreak\"\n", buf.String())
+
+	// what was written can be read back
+	reader := NewNDJSONReader(MakeIterator(buf.String()))
+	record, err := reader.Next()
+	assert.Nil(err)
+	assert.Equal(map[string]interface{}{"a": []interface{}{float64(1)}, "b": float64(2)}, record)
+	record, err = reader.Next()
+	assert.Nil(err)
+	assert.Equal("line\nbreak", record)
+	_, err = reader.Next()
+	assert.Equal(io.EOF, err)
+}
@@ -0,0 +1,97 @@
+package gogojson
+
+import (
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NumberMode selects how numbers are represented in parsed values
+type NumberMode int
+
+const (
+	// NumberFloat64 turns every number into a float64, which is the default
+	NumberFloat64 NumberMode = iota
+	// NumberString keeps the exact literal as a Number
+	NumberString
+	// NumberInt64 returns integral numbers that fit as int64 and everything
+	// else as float64
+	NumberInt64
+	// NumberBig returns integral numbers as *big.Int and everything else as
+	// *big.Float, both without losing precision
+	NumberBig
+)
+
+// Number is the literal text of a json number
+type Number string
+
+// String returns the literal
+func (n Number) String() string {
+	return string(n)
+}
+
+// Float64 returns the number as float64
+func (n Number) Float64() (float64, error) {
+	return strconv.ParseFloat(string(n), 64)
+}
+
+// Int64 returns the number as int64, which fails for fractions and numbers
+// that don't fit
+func (n Number) Int64() (int64, error) {
+	return strconv.ParseInt(string(n), 10, 64)
+}
+
+var numberLiteralRegex = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+func isNumberLiteral(literal string) bool {
+	return numberLiteralRegex.MatchString(literal)
+}
+
+func isIntegral(literal string) bool {
+	return !strings.ContainsAny(literal, ".eE")
+}
+
+// convertNumber turns the literal of a NUM token into the value mode asks for
+func convertNumber(token *Token, mode NumberMode) (interface{}, error) {
+	value, err := numberValue(token.Raw, mode)
+	if err != nil {
+		return nil, newSyntaxError(token.Position, "Number '%s' is out of range", token.Raw)
+	}
+
+	return value, nil
+}
+
+// numberValue converts a valid number literal into the value mode asks for
+func numberValue(literal string, mode NumberMode) (interface{}, error) {
+	switch mode {
+	case NumberString:
+		return Number(literal), nil
+	case NumberInt64:
+		if isIntegral(literal) {
+			if i, err := strconv.ParseInt(literal, 10, 64); err == nil {
+				return i, nil
+			}
+		}
+	case NumberBig:
+		if isIntegral(literal) {
+			i, _ := new(big.Int).SetString(literal, 10)
+			return i, nil
+		}
+
+		return bigFloat(literal)
+	}
+
+	return strconv.ParseFloat(literal, 64)
+}
+
+// bigFloat parses a number literal without losing any of its digits
+func bigFloat(literal string) (*big.Float, error) {
+	// roughly four bits per digit keep every digit of the literal
+	prec := uint(len(literal)) * 4
+	if prec < 64 {
+		prec = 64
+	}
+	f, _, err := big.ParseFloat(literal, 10, prec, big.ToNearestEven)
+	return f, err
+}
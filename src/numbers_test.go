@@ -0,0 +1,99 @@
+package gogojson
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const idJSON = `{"id": 9007199254740993, "small": -12, "price": 0.1, "exp": 1e2, "huge": 123456789012345678901234567890}`
+
+func TestNumberModes(t *testing.T) {
+	assert := assert.New(t)
+	tokens, err := Tokenize(MakeIterator(idJSON))
+	assert.Nil(err)
+
+	parsed, err := Parse(tokens)
+	assert.Nil(err)
+	assert.Equal(float64(9007199254740992), parsed["id"])
+
+	parsed, err = ParseOptions{Numbers: NumberString}.Parse(tokens)
+	assert.Nil(err)
+	assert.Equal(Number("9007199254740993"), parsed["id"])
+	assert.Equal(Number("0.1"), parsed["price"])
+	assert.Equal(Number("123456789012345678901234567890"), parsed["huge"])
+
+	parsed, err = ParseOptions{Numbers: NumberInt64}.Parse(tokens)
+	assert.Nil(err)
+	assert.Equal(int64(9007199254740993), parsed["id"])
+	assert.Equal(int64(-12), parsed["small"])
+	assert.Equal(0.1, parsed["price"])
+	assert.Equal(float64(100), parsed["exp"])
+	assert.Equal(1.2345678901234568e+29, parsed["huge"])
+
+	parsed, err = ParseOptions{Numbers: NumberBig}.Parse(tokens)
+	assert.Nil(err)
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	assert.Equal(0, huge.Cmp(parsed["huge"].(*big.Int)))
+	assert.Equal(int64(9007199254740993), parsed["id"].(*big.Int).Int64())
+	assert.Equal("0.1", parsed["price"].(*big.Float).Text('g', -1))
+	assert.Equal("100", parsed["exp"].(*big.Float).Text('g', -1))
+}
+
+func TestNumberModeUnmarshal(t *testing.T) {
+	assert := assert.New(t)
+	var parsed interface{}
+	err := ParseOptions{Numbers: NumberString}.Unmarshal([]byte(`[12345678901234567890, -1.5e-3]`), &parsed)
+
+	assert.Nil(err)
+	assert.Equal([]interface{}{Number("12345678901234567890"), Number("-1.5e-3")}, parsed)
+
+	n := parsed.([]interface{})[1].(Number)
+	f, err := n.Float64()
+	assert.Nil(err)
+	assert.Equal(-0.0015, f)
+	_, err = n.Int64()
+	assert.NotNil(err)
+	assert.Equal("-1.5e-3", n.String())
+}
+
+func TestNumberModesBeyondFloat64(t *testing.T) {
+	assert := assert.New(t)
+	_, err := GogoJson(`[1e400]`)
+	assert.EqualError(err, "Number '1e400' is out of range (line 1, column 2, offset 1)")
+
+	parsed, err := ParseOptions{Numbers: NumberString}.GogoJson(`[1e400]`)
+	assert.Nil(err)
+	assert.Equal([]interface{}{Number("1e400")}, parsed)
+
+	parsed, err = ParseOptions{Numbers: NumberBig}.GogoJson(`[-1e400]`)
+	assert.Nil(err)
+	assert.Equal("-1e+400", parsed.([]interface{})[0].(*big.Float).Text('g', -1))
+
+	var f big.Float
+	assert.Nil(Unmarshal([]byte(`1e400`), &f))
+	assert.Equal("1e+400", f.Text('g', -1))
+
+	var n Number
+	assert.Nil(Unmarshal([]byte(`1e400`), &n))
+	assert.Equal(Number("1e400"), n)
+	_, err = n.Float64()
+	assert.NotNil(err)
+}
+
+func TestNumberModeMarshal(t *testing.T) {
+	assert := assert.New(t)
+	opts := MarshalOptions{SortKeys: true}
+	for _, mode := range []NumberMode{NumberString, NumberInt64, NumberBig} {
+		parsed, err := ParseOptions{Numbers: mode}.GogoJson(`{"a": 9007199254740993, "b": 0.1, "c": [-7]}`)
+		assert.Nil(err)
+
+		serialized, err := opts.Marshal(parsed)
+		assert.Nil(err)
+		assert.Equal(`{"a":9007199254740993,"b":0.1,"c":[-7]}`, string(serialized))
+	}
+
+	_, err := Marshal(Number("12abc"))
+	assert.Equal(&UnsupportedValueError{Value: `Number("12abc")`}, err)
+}
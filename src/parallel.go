@@ -0,0 +1,131 @@
+package gogojson
+
+import (
+	"strings"
+	"sync"
+)
+
+// scanElements pre-scans a document whose top level value is an array and
+// returns the elements as substrings. Only the structural characters are
+// looked at, so anything that doesn't even have the shape of an array is
+// reported as not ok, the elements themselves are validated when parsed
+func scanElements(source string) ([]string, bool) {
+	i := 0
+	if strings.HasPrefix(source, byteOrderMark) {
+		i = len(byteOrderMark)
+	}
+	for i < len(source) && isWhitespace(source[i:i+1]) {
+		i++
+	}
+	if i == len(source) || source[i] != '[' {
+		return nil, false
+	}
+
+	elements := make([]string, 0)
+	depth := 0
+	start := -1
+	inString := false
+
+	for ; i < len(source); i++ {
+		c := source[i]
+		if inString {
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		// the first character of an element directly inside the array
+		if depth == 1 && start < 0 && !isWhitespace(source[i:i+1]) && c != ',' && c != ']' {
+			start = i
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth > 0 {
+				continue
+			} else if c != ']' {
+				// the array is closed by a brace
+				return nil, false
+			}
+
+			if start >= 0 {
+				elements = append(elements, source[start:i])
+			} else if len(elements) > 0 {
+				// trailing comma
+				return nil, false
+			}
+			rest := strings.TrimLeft(source[i+1:], " \n\t\r")
+			return elements, rest == ""
+		case ',':
+			if depth == 1 {
+				if start < 0 {
+					// missing element
+					return nil, false
+				}
+				elements = append(elements, source[start:i])
+				start = -1
+			}
+		}
+	}
+
+	// unterminated array
+	return nil, false
+}
+
+// parseParallel parses the elements of a top level array concurrently. It
+// returns false if the document is no array or invalid, in which case the
+// regular parser has to report the error in its context
+func (opts ParseOptions) parseParallel(source string) (interface{}, bool) {
+	elements, ok := scanElements(source)
+	if !ok {
+		return nil, false
+	}
+
+	values := make([]interface{}, len(elements))
+	errs := make([]error, len(elements))
+	chunk := (len(elements) + opts.Parallel - 1) / opts.Parallel
+
+	var wg sync.WaitGroup
+	for from := 0; from < len(elements); from += chunk {
+		to := from + chunk
+		if to > len(elements) {
+			to = len(elements)
+		}
+
+		wg.Add(1)
+		go func(from, to int) {
+			defer wg.Done()
+			for i := from; i < to; i++ {
+				values[i], errs[i] = opts.parseDocument(&lexerSource{lexer: lexer{elementIterator(elements[i])}})
+			}
+		}(from, to)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, false
+		}
+	}
+
+	return values, true
+}
+
+// elementIterator iterates over a single element. Unlike MakeIterator it
+// doesn't skip a byte order mark, which is only valid at the very start
+func elementIterator(element string) *StringIterator {
+	return &StringIterator{
+		source: element,
+		line:   1,
+		row:    1,
+		length: uint64(len(element)),
+	}
+}
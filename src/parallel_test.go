@@ -0,0 +1,95 @@
+package gogojson
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func bigArray(n int) string {
+	elements := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		elements = append(elements, fmt.Sprintf(`{"id": %d, "text": "a, [b] {c} \"d\" ä", "list": [%d, [], {}], "flag": %t}`, i, i*2, i%2 == 0))
+	}
+
+	return "[\n  " + strings.Join(elements, ",\n  ") + "\n]\n"
+}
+
+func TestParseParallel(t *testing.T) {
+	assert := assert.New(t)
+	source := bigArray(1000)
+
+	expected, err := GogoJson(source)
+	assert.Nil(err)
+
+	for _, workers := range []int{2, 3, 8, 5000} {
+		parsed, err := ParseOptions{Parallel: workers}.GogoJson(source)
+
+		assert.Nil(err)
+		assert.Equal(expected, parsed)
+	}
+
+	var records []struct {
+		ID   int64
+		Text string
+		List []interface{}
+	}
+	err = ParseOptions{Parallel: 4, Numbers: NumberInt64}.Unmarshal([]byte(source), &records)
+	assert.Nil(err)
+	assert.Len(records, 1000)
+	assert.Equal(int64(999), records[999].ID)
+	assert.Equal([]interface{}{int64(1998), []interface{}{}, map[string]interface{}{}}, records[999].List)
+}
+
+func TestParseParallelShapes(t *testing.T) {
+	assert := assert.New(t)
+	documents := []string{
+		`[]`,
+		` [ ] `,
+		"\uFEFF[1, 2]",
+		`[[1, [2]], "x", null, -1.5e3]`,
+		`{"not": "an array"}`,
+		`"string"`,
+		`  42`,
+	}
+
+	for _, source := range documents {
+		expected, err := GogoJson(source)
+		assert.Nil(err, source)
+
+		parsed, err := ParseOptions{Parallel: 4}.GogoJson(source)
+		assert.Nil(err, source)
+		assert.Equal(expected, parsed, source)
+	}
+}
+
+func TestParseParallelErrors(t *testing.T) {
+	assert := assert.New(t)
+	documents := []string{
+		`[1, 2,]`,
+		`[1,, 2]`,
+		`[,]`,
+		`[1, 2`,
+		`[1, 2] 3`,
+		`[1, 2]]`,
+		`[1, {"a" 1}, 3]`,
+		"[\n  1,\n  tru,\n  {\"a\": !}\n]",
+		"[\"ä\", \"b\xff\"]",
+		`[1 2]`,
+		`[{"a": [}]`,
+		"[\uFEFF1]",
+		`[1}`,
+		`[1, 2}`,
+		`[{"a":1}}`,
+	}
+
+	for _, source := range documents {
+		_, expected := GogoJson(source)
+		assert.NotNil(expected, source)
+
+		_, err := ParseOptions{Parallel: 4}.GogoJson(source)
+		assert.Equal(expected, err, source)
+	}
+}
@@ -1,66 +1,278 @@
 package gogojson
 
 import (
-	"fmt"
+	"strconv"
 )
 
-func parseRecursive(tokens []*Token, host map[string]interface{}) ([]*Token, map[string]interface{}) {
-	var key string
-	var next *Token
+// ParseOptions controls how tokens are turned into values. The zero value is
+// what Parse, ParseArray and Unmarshal use
+type ParseOptions struct {
+	// Numbers selects the representation of numbers
+	Numbers NumberMode
+	// Parallel is the number of goroutines Unmarshal and GogoJson use to
+	// parse the elements of a top level array. Values below 2 parse
+	// sequentially
+	Parallel int
+}
+
+// tokenSource hands out the tokens the parser works on one at a time
+type tokenSource interface {
+	// next returns the next token, which is nil at the end of input
+	next() (*Token, error)
+	// peek returns the next token without consuming it
+	peek() (*Token, error)
+}
+
+// sliceSource is a tokenSource over the result of Tokenize
+type sliceSource struct {
+	tokens []*Token
+}
+
+func (source *sliceSource) next() (*Token, error) {
+	token, err := source.peek()
+	if token != nil {
+		source.tokens = source.tokens[1:]
+	}
+
+	return token, err
+}
+
+func (source *sliceSource) peek() (*Token, error) {
+	if len(source.tokens) == 0 {
+		return nil, nil
+	}
+
+	return source.tokens[0], nil
+}
+
+type parser struct {
+	opts   ParseOptions
+	tokens tokenSource
+	// position of the last token, the end of input is reported there
+	last Position
+}
+
+func newParser(opts ParseOptions, tokens tokenSource) *parser {
+	return &parser{opts: opts, tokens: tokens}
+}
+
+func (p *parser) parseObject() (map[string]interface{}, error) {
+	host := newMap()
+
+	// empty object
+	if closed, err := p.skipIfPunctuation("}"); closed || err != nil {
+		return host, err
+	}
 
-	for len(tokens) > 0 {
-		key, tokens = assertString(tokens)
-		tokens = skipPunctuation(tokens, ":")
-		next, tokens = shift(tokens)
+	for {
+		key, err := p.assertString()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.skipPunctuation(":"); err != nil {
+			return nil, err
+		}
+		if host[key], err = p.parseValue(); err != nil {
+			return nil, withPointer(err, key)
+		}
+
+		if closed, err := p.skipIfPunctuation("}"); closed || err != nil {
+			return host, err
+		}
+		if err = p.skipPunctuation(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseArray() ([]interface{}, error) {
+	host := newArray()
+
+	// empty array
+	if closed, err := p.skipIfPunctuation("]"); closed || err != nil {
+		return host, err
+	}
 
-		if next.Type == STRING || next.Type == NUM || next.Type == BOOL || next.Type == NULL {
-			host[key] = next.Value
-		} else if next.Value == "{" {
-			tokens, host[key] = parseRecursive(tokens, newMap())
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, withPointer(err, strconv.Itoa(len(host)))
 		}
+		host = append(host, value)
 
-		if len(tokens) > 0 && tokens[0].Type == PUNC && tokens[0].Value == "}" {
-			tokens = skipPunctuation(tokens, "}")
-			break
+		if closed, err := p.skipIfPunctuation("]"); closed || err != nil {
+			return host, err
+		}
+		if err = p.skipPunctuation(","); err != nil {
+			return nil, err
 		}
-		tokens = skipPunctuation(tokens, ",")
+	}
+}
+
+func (p *parser) parseValue() (interface{}, error) {
+	next, err := p.shift()
+	if err != nil {
+		return nil, err
+	}
+
+	if next.Type == NUM && (p.opts.Numbers != NumberFloat64 || next.Value == nil) {
+		return convertNumber(next, p.opts.Numbers)
+	} else if next.Type == STRING || next.Type == NUM || next.Type == BOOL || next.Type == NULL {
+		return next.Value, nil
+	} else if next.Value == "{" {
+		return p.parseObject()
+	} else if next.Value == "[" {
+		return p.parseArray()
+	}
+
+	return nil, newSyntaxError(next.Position, "Unexpected token '%v'", next.Value)
+}
+
+// withPointer prefixes the pointer of a *SyntaxError with the token of the
+// value it happened in, while the error travels up. Errors are copied, as
+// the same one may be returned more than once
+func withPointer(err error, token string) error {
+	syntaxErr, ok := err.(*SyntaxError)
+	if !ok {
+		return err
 	}
 
-	return tokens, host
+	copied := *syntaxErr
+	copied.Pointer = append(Pointer{token}, syntaxErr.Pointer...)
+	return &copied
 }
 
-func shift(tokens []*Token) (*Token, []*Token) {
-	return tokens[0], tokens[1:]
+// shift consumes the next token, running out of tokens is an error
+func (p *parser) shift() (*Token, error) {
+	token, err := p.tokens.next()
+	if err != nil {
+		return nil, err
+	} else if token == nil {
+		return nil, newSyntaxError(p.last, "Unexpected end of input")
+	}
+
+	p.last = token.Position
+	return token, nil
 }
 
-func assertString(tokens []*Token) (string, []*Token) {
-	key, tokens := shift(tokens)
+func (p *parser) assertString() (string, error) {
+	key, err := p.shift()
+	if err != nil {
+		return "", err
+	}
 	if key.Type == STRING {
-		return key.Value.(string), tokens
+		return key.Value.(string), nil
+	}
+
+	return "", newSyntaxError(key.Position, "Expected string key, instead got a %s", key.Type)
+}
+
+// assertEnd makes sure nothing follows the top level value
+func (p *parser) assertEnd() error {
+	token, err := p.tokens.peek()
+	if err != nil {
+		return err
+	} else if token != nil {
+		return newSyntaxError(token.Position, "Unexpected token '%v' after end of document", token.Value)
+	}
+
+	return nil
+}
+
+// skipIfPunctuation consumes the next token only if it is the punctuation
+func (p *parser) skipIfPunctuation(value string) (bool, error) {
+	token, err := p.tokens.peek()
+	if err != nil || token == nil || token.Type != PUNC || token.Value != value {
+		return false, err
+	}
+
+	_, err = p.shift()
+	return true, err
+}
+
+func (p *parser) skipPunctuation(when string) error {
+	t, err := p.shift()
+	if err != nil {
+		return err
+	}
+	if t.Type == PUNC && t.Value == when {
+		return nil
 	}
 
-	panic(fmt.Sprintf("Expected string key, instead got a %s", key.Type))
+	return newSyntaxError(t.Position, "Expected punctuation with value '%s', instead got: '%v'", when, t.Value)
 }
 
 func newMap() map[string]interface{} {
 	return make(map[string]interface{})
 }
 
+func newArray() []interface{} {
+	return make([]interface{}, 0)
+}
+
 // Parse is a function that takes in a list of Token Pointers and returns a
 // generic map type for the json object
-func Parse(input []*Token) map[string]interface{} {
+func Parse(input []*Token) (map[string]interface{}, error) {
+	return ParseOptions{}.Parse(input)
+}
+
+// ParseArray works like Parse, but for documents whose top level value is
+// an array
+func ParseArray(input []*Token) ([]interface{}, error) {
+	return ParseOptions{}.ParseArray(input)
+}
+
+// Parse works like the package level Parse, but applies the options
+func (opts ParseOptions) Parse(input []*Token) (map[string]interface{}, error) {
+	p := newParser(opts, &sliceSource{input})
+
 	// skip curly bracket
-	tokens := skipPunctuation(input, "{")
-	_, out := parseRecursive(tokens, newMap())
+	if err := p.skipPunctuation("{"); err != nil {
+		return nil, err
+	}
 
-	return out
+	out, err := p.parseObject()
+	if err == nil {
+		err = p.assertEnd()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
 }
 
-func skipPunctuation(tokens []*Token, when string) []*Token {
-	t, tokens := tokens[0], tokens[1:]
-	if t.Type == PUNC && t.Value == when {
-		return tokens
+// ParseArray works like the package level ParseArray, but applies the options
+func (opts ParseOptions) ParseArray(input []*Token) ([]interface{}, error) {
+	p := newParser(opts, &sliceSource{input})
+
+	// skip squared bracket
+	if err := p.skipPunctuation("["); err != nil {
+		return nil, err
+	}
+
+	out, err := p.parseArray()
+	if err == nil {
+		err = p.assertEnd()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// parseDocument parses a single top level value of any type
+func (opts ParseOptions) parseDocument(tokens tokenSource) (interface{}, error) {
+	p := newParser(opts, tokens)
+
+	out, err := p.parseValue()
+	if err == nil {
+		err = p.assertEnd()
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	panic(fmt.Sprintf("Expected punctuation with value '%s', instead got: '%s'", when, t.Value.(string)))
+	return out, nil
 }
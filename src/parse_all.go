@@ -0,0 +1,186 @@
+package gogojson
+
+import (
+	"context"
+	"sync"
+)
+
+// Result is the outcome of parsing one document with ParseAll
+type Result struct {
+	// Index is the position of the document in the input channel
+	Index int
+	Value interface{}
+	Err   error
+}
+
+// ParseAllOptions controls ParseAll
+type ParseAllOptions struct {
+	ParseOptions
+	// Ordered emits the results in the order of the documents instead of
+	// as soon as they are ready
+	Ordered bool
+}
+
+type parseJob struct {
+	index int
+	doc   []byte
+}
+
+// ParseAll parses every document received from docs on a pool of workers
+// goroutines. The results are sent as soon as they are ready and the channel
+// is closed once docs is closed and everything is parsed. Either read the
+// results to the end or cancel ctx, which stops all workers and closes the
+// channel early
+func ParseAll(ctx context.Context, docs <-chan []byte, workers int) <-chan Result {
+	return ParseAllOptions{}.ParseAll(ctx, docs, workers)
+}
+
+// ParseAll works like the package level ParseAll, but applies the options
+func (opts ParseAllOptions) ParseAll(ctx context.Context, docs <-chan []byte, workers int) <-chan Result {
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan parseJob)
+	results := make(chan Result, workers)
+
+	// number the documents so the results can be told apart
+	go func() {
+		defer close(jobs)
+		for index := 0; ; index++ {
+			select {
+			case doc, ok := <-docs:
+				if !ok {
+					return
+				}
+				select {
+				case jobs <- parseJob{index: index, doc: doc}:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			// every worker keeps its tokens across documents
+			var buf tokenBuffer
+			for job := range jobs {
+				result := Result{Index: job.index}
+				result.Value, result.Err = opts.parseReusing(job.doc, &buf)
+
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	if opts.Ordered {
+		return reorderResults(ctx, results)
+	}
+	return results
+}
+
+// parseReusing parses doc, reading its tokens into the memory of buf
+func (opts ParseOptions) parseReusing(doc []byte, buf *tokenBuffer) (interface{}, error) {
+	tokens, err := buf.tokenize(MakeIterator(string(doc)))
+
+	var value interface{}
+	if err == nil {
+		value, err = opts.parseDocument(&sliceSource{tokens})
+	}
+	buf.release()
+
+	return value, err
+}
+
+// tokenBuffer holds the tokens of one document at a time. Both the tokens
+// and the list of pointers to them are reused for the next document
+type tokenBuffer struct {
+	values []Token
+	tokens []*Token
+}
+
+func (buf *tokenBuffer) tokenize(source Iterator) ([]*Token, error) {
+	iter := lexer{source}
+
+	for {
+		buf.values = append(buf.values, Token{})
+		ok, err := iter.readToken(&buf.values[len(buf.values)-1])
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			buf.values = buf.values[:len(buf.values)-1]
+			break
+		}
+	}
+
+	// the values may have moved while growing, so point to them only now
+	for i := range buf.values {
+		buf.tokens = append(buf.tokens, &buf.values[i])
+	}
+
+	return buf.tokens, nil
+}
+
+// release empties the buffer without keeping the values of the last
+// document alive
+func (buf *tokenBuffer) release() {
+	for i := range buf.values {
+		buf.values[i] = Token{}
+	}
+	for i := range buf.tokens {
+		buf.tokens[i] = nil
+	}
+
+	buf.values = buf.values[:0]
+	buf.tokens = buf.tokens[:0]
+}
+
+// reorderResults holds back results until all results of earlier documents
+// have been sent
+func reorderResults(ctx context.Context, results <-chan Result) <-chan Result {
+	out := make(chan Result)
+
+	go func() {
+		defer close(out)
+
+		pending := make(map[int]Result)
+		next := 0
+		for result := range results {
+			pending[result.Index] = result
+
+			for {
+				ready, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+
+				select {
+				case out <- ready:
+				case <-ctx.Done():
+					return
+				}
+				next++
+			}
+		}
+	}()
+
+	return out
+}
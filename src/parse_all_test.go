@@ -0,0 +1,140 @@
+package gogojson
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sendDocs(docs ...string) <-chan []byte {
+	out := make(chan []byte, len(docs))
+	for _, doc := range docs {
+		out <- []byte(doc)
+	}
+	close(out)
+
+	return out
+}
+
+func TestParseAll(t *testing.T) {
+	assert := assert.New(t)
+	docs := []string{}
+	for i := 0; i < 200; i++ {
+		docs = append(docs, fmt.Sprintf(`{"index": %d, "tags": ["a", "b"]}`, i))
+	}
+	docs = append(docs, `{"broken": }`)
+
+	seen := make(map[int]bool)
+	errors := 0
+	for result := range ParseAll(context.Background(), sendDocs(docs...), 8) {
+		seen[result.Index] = true
+		if result.Err != nil {
+			errors++
+			assert.Equal(200, result.Index)
+			assert.IsType(&SyntaxError{}, result.Err)
+			continue
+		}
+
+		value := result.Value.(map[string]interface{})
+		assert.Equal(float64(result.Index), value["index"])
+		assert.Equal([]interface{}{"a", "b"}, value["tags"])
+	}
+
+	assert.Len(seen, 201)
+	assert.Equal(1, errors)
+}
+
+func TestParseAllOrdered(t *testing.T) {
+	assert := assert.New(t)
+	docs := []string{}
+	for i := 0; i < 100; i++ {
+		// documents of very different sizes finish out of order
+		docs = append(docs, fmt.Sprintf(`[%d, "%s"]`, i, strings.Repeat(" ", (i%7)*1000)))
+	}
+
+	opts := ParseAllOptions{Ordered: true, ParseOptions: ParseOptions{Numbers: NumberInt64}}
+	index := 0
+	for result := range opts.ParseAll(context.Background(), sendDocs(docs...), 4) {
+		assert.Nil(result.Err)
+		assert.Equal(index, result.Index)
+		assert.Equal(int64(index), result.Value.([]interface{})[0])
+		index++
+	}
+
+	assert.Equal(100, index)
+}
+
+func TestParseAllCancel(t *testing.T) {
+	assert := assert.New(t)
+	before := runtime.NumGoroutine()
+
+	for _, ordered := range []bool{false, true} {
+		ctx, cancel := context.WithCancel(context.Background())
+		docs := make(chan []byte)
+		go func() {
+			for {
+				select {
+				case docs <- []byte(`{"endless": true}`):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		results := ParseAllOptions{Ordered: ordered}.ParseAll(ctx, docs, 4)
+		for i := 0; i < 10; i++ {
+			assert.Nil((<-results).Err)
+		}
+		cancel()
+
+		// the channel gets closed without anybody reading the rest
+		deadline := time.After(time.Second)
+	drain:
+		for {
+			select {
+			case _, ok := <-results:
+				if !ok {
+					break drain
+				}
+			case <-deadline:
+				t.Fatal("results were not closed after cancel")
+			}
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(before, runtime.NumGoroutine())
+}
+
+func TestTokenBufferReuse(t *testing.T) {
+	assert := assert.New(t)
+	var buf tokenBuffer
+
+	tokens, err := buf.tokenize(MakeIterator(`[1, "a", {"b": null}]`))
+	assert.Nil(err)
+	assert.Len(tokens, 11)
+	first := tokens[0]
+	buf.release()
+	assert.Equal(Token{}, *first)
+
+	// the next document is read into the same tokens
+	tokens, err = buf.tokenize(MakeIterator(`{"c": true}`))
+	assert.Nil(err)
+	assert.Same(first, tokens[0])
+	assert.Equal(Token{Type: PUNC, Value: "{", Position: Position{Line: 1, Column: 1, Offset: 0}}, *tokens[0])
+	assert.Len(tokens, 5)
+	buf.release()
+
+	_, err = buf.tokenize(MakeIterator(`[1, tru]`))
+	assert.IsType(&SyntaxError{}, err)
+	buf.release()
+	assert.Empty(buf.values)
+}
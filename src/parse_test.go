@@ -19,10 +19,11 @@ var parseJSON = `{
 
 func TestParse(t *testing.T) {
 	iterator := MakeIterator(parseJSON)
-	tokens := Tokenize(iterator)
-	parsed := Parse(tokens)
+	tokens, _ := Tokenize(iterator)
+	parsed, err := Parse(tokens)
 
 	assert := assert.New(t)
+	assert.Nil(err)
 	name := parsed["name"]
 	age := parsed["age"]
 	assert.Equal("Peter", name, "Age is not 42")
@@ -31,32 +32,107 @@ func TestParse(t *testing.T) {
 	fmt.Println("Parsed:", parsed)
 }
 
-func TestParsePanicKey(t *testing.T) {
+var arrayJSON = `{
+  "tags": ["family", "guy"],
+  "empty": [],
+  "matrix": [[1, 2], [3]],
+  "children": [
+    {"name": "Meg"},
+    {"name": "Chris", "hobbies": ["drawing"]}
+  ]
+}`
+
+func TestParseArray(t *testing.T) {
+	iterator := MakeIterator(arrayJSON)
+	tokens, _ := Tokenize(iterator)
+	parsed, err := Parse(tokens)
+
+	assert := assert.New(t)
+	assert.Equal([]interface{}{"family", "guy"}, parsed["tags"])
+	assert.Equal([]interface{}{}, parsed["empty"])
+	assert.Equal([]interface{}{
+		[]interface{}{float64(1), float64(2)},
+		[]interface{}{float64(3)},
+	}, parsed["matrix"])
+
+	assert.Nil(err)
+
+	children := parsed["children"].([]interface{})
+	assert.Len(children, 2)
+	assert.Equal("Meg", children[0].(map[string]interface{})["name"])
+	assert.Equal([]interface{}{"drawing"}, children[1].(map[string]interface{})["hobbies"])
+}
+
+func TestParseTopLevelArray(t *testing.T) {
+	iterator := MakeIterator(`[{"name": "Stewie"}, [true, null], "Brian", {}]`)
+	tokens, _ := Tokenize(iterator)
+	parsed, err := ParseArray(tokens)
+
 	assert := assert.New(t)
+	assert.Nil(err)
+	assert.Equal([]interface{}{
+		map[string]interface{}{"name": "Stewie"},
+		[]interface{}{true, nil},
+		"Brian",
+		map[string]interface{}{},
+	}, parsed)
+}
 
-	defer func() {
-		if r := recover(); r != nil {
-			assert.Equal(r, "Expected string key, instead got a NUM")
-		}
-	}()
+func TestParseErrorKey(t *testing.T) {
+	assert := assert.New(t)
 
 	iterator := MakeIterator(`{123: 123}`)
-	tokens := Tokenize(iterator)
-	Parse(tokens)
+	tokens, _ := Tokenize(iterator)
+	_, err := Parse(tokens)
+
+	assert.Equal(&SyntaxError{
+		Msg:      "Expected string key, instead got a NUM",
+		Position: Position{Line: 1, Column: 2, Offset: 1},
+	}, err)
+}
+
+func TestParseErrorPunc(t *testing.T) {
+	assert := assert.New(t)
 
+	iterator := MakeIterator(`{
+  "hello", 123}`)
+	tokens, _ := Tokenize(iterator)
+	_, err := Parse(tokens)
+
+	assert.Equal(&SyntaxError{
+		Msg:      "Expected punctuation with value ':', instead got: ','",
+		Position: Position{Line: 2, Column: 10, Offset: 11},
+	}, err)
+	assert.Equal("Expected punctuation with value ':', instead got: ',' (line 2, column 10, offset 11)", err.Error())
 }
 
-func TestParsePanicPunc(t *testing.T) {
+func TestParseErrorEndOfInput(t *testing.T) {
 	assert := assert.New(t)
 
-	defer func() {
-		if r := recover(); r != nil {
-			assert.Equal(r, "Expected punctuation with value ':', instead got: ','")
-		}
-	}()
+	iterator := MakeIterator(`{"a": [1, 2`)
+	tokens, _ := Tokenize(iterator)
+	_, err := Parse(tokens)
+
+	assert.Equal(&SyntaxError{
+		Msg:      "Unexpected end of input",
+		Position: Position{Line: 1, Column: 11, Offset: 10},
+		Pointer:  Pointer{"a"},
+	}, err)
+}
+
+func TestParseErrorNumberRange(t *testing.T) {
+	assert := assert.New(t)
 
-	iterator := MakeIterator(`{"hello", 123}`)
-	tokens := Tokenize(iterator)
-	Parse(tokens)
+	iterator := MakeIterator(`{"a": [1, 1e400]}`)
+	tokens, err := Tokenize(iterator)
+	assert.Nil(err)
+	assert.Nil(tokens[6].Value)
+	assert.Equal("1e400", tokens[6].Raw)
 
+	_, err = Parse(tokens)
+	assert.Equal(&SyntaxError{
+		Msg:      "Number '1e400' is out of range",
+		Position: Position{Line: 1, Column: 11, Offset: 10},
+		Pointer:  Pointer{"a", "1"},
+	}, err)
 }
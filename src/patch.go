@@ -0,0 +1,472 @@
+package gogojson
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Operation is a single operation of a JSON Patch as defined by RFC 6902.
+// Op is one of add, remove, replace, move, copy and test. From is only used
+// by move and copy, Value only by add, replace and test
+type Operation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value"`
+}
+
+// Patch is a list of operations that are applied in order
+type Patch []Operation
+
+// ParsePatch parses a JSON Patch document. Operations that are malformed are
+// reported as a *PatchError, malformed json as a *SyntaxError
+func ParsePatch(source string) (Patch, error) {
+	return ParseOptions{}.ParsePatch(source)
+}
+
+// ParsePatch works like the package level ParsePatch, but applies the
+// options to the values of the operations. Patches for trees with exact
+// numbers should be parsed with the same Numbers mode, so test compares
+// them exactly as well
+func (opts ParseOptions) ParsePatch(source string) (Patch, error) {
+	tree, err := opts.GogoJson(source)
+	if err != nil {
+		return nil, err
+	}
+
+	list, ok := tree.([]interface{})
+	if !ok {
+		return nil, errors.New("A patch has to be an array of operations")
+	}
+
+	patch := make(Patch, len(list))
+	for i, item := range list {
+		if err := patch[i].parse(item); err != nil {
+			return nil, &PatchError{Index: i, Op: patch[i].Op, Err: err}
+		}
+	}
+
+	return patch, nil
+}
+
+// UnmarshalJSON decodes an operation, checking its members like ParsePatch
+func (op *Operation) UnmarshalJSON(data []byte) error {
+	tree, err := GogoJson(string(data))
+	if err != nil {
+		return err
+	}
+
+	return op.parse(tree)
+}
+
+// MarshalJSON encodes an operation with only the members its op uses
+func (op Operation) MarshalJSON() ([]byte, error) {
+	members := []string{"op", op.Op, "path", op.Path}
+	if op.Op == "move" || op.Op == "copy" {
+		members = append(members, "from", op.From)
+	}
+
+	var out strings.Builder
+	out.WriteByte('{')
+	for i := 0; i < len(members); i += 2 {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		key, _ := Marshal(members[i])
+		value, _ := Marshal(members[i+1])
+		out.Write(key)
+		out.WriteByte(':')
+		out.Write(value)
+	}
+	if op.Op == "add" || op.Op == "replace" || op.Op == "test" {
+		value, err := Marshal(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		out.WriteString(`,"value":`)
+		out.Write(value)
+	}
+	out.WriteByte('}')
+
+	return []byte(out.String()), nil
+}
+
+// parse fills the operation from its generic representation
+func (op *Operation) parse(tree interface{}) error {
+	members, ok := tree.(map[string]interface{})
+	if !ok {
+		return errors.New("Operation has to be an object")
+	}
+
+	var err error
+	if op.Op, err = stringMember(members, "op"); err != nil {
+		return err
+	}
+	if op.Path, err = stringMember(members, "path"); err != nil {
+		return err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		value, ok := members["value"]
+		if !ok {
+			return errors.New("Missing member 'value'")
+		}
+		op.Value = value
+	case "move", "copy":
+		if op.From, err = stringMember(members, "from"); err != nil {
+			return err
+		}
+	case "remove":
+	default:
+		return fmt.Errorf("Unknown op '%s'", op.Op)
+	}
+
+	return nil
+}
+
+func stringMember(members map[string]interface{}, key string) (string, error) {
+	value, ok := members[key]
+	if !ok {
+		return "", fmt.Errorf("Missing member '%s'", key)
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("Member '%s' has to be a string", key)
+	}
+
+	return s, nil
+}
+
+// Apply applies the patch to a parsed tree and returns the result. The patch
+// is atomic: if any operation fails, a *PatchError is returned and none of
+// the operations before it take effect. tree itself is never changed
+func (patch Patch) Apply(tree interface{}) (interface{}, error) {
+	// all operations work on a copy, which is simply dropped on failure
+	result := deepCopy(tree)
+
+	for i, op := range patch {
+		var err error
+		if result, err = op.apply(result); err != nil {
+			return nil, &PatchError{Index: i, Op: op.Op, Err: err}
+		}
+	}
+
+	return result, nil
+}
+
+func (op *Operation) apply(tree interface{}) (interface{}, error) {
+	path, err := ParsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add":
+		return addValue(tree, path, deepCopy(op.Value))
+	case "remove":
+		return path.Remove(tree)
+	case "replace":
+		if _, err := path.Resolve(tree); err != nil {
+			return nil, err
+		}
+		return path.Set(tree, deepCopy(op.Value))
+	case "test":
+		value, err := path.Resolve(tree)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(value, op.Value) {
+			return nil, fmt.Errorf("Test failed, the value at '%s' differs", op.Path)
+		}
+		return tree, nil
+	case "move", "copy":
+		from, err := ParsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := from.Resolve(tree)
+		if err != nil {
+			return nil, err
+		}
+
+		if op.Op == "copy" {
+			return addValue(tree, path, deepCopy(value))
+		}
+		if len(path) > len(from) && isPointerPrefix(from, path) {
+			return nil, fmt.Errorf("Cannot move '%s' into its own child '%s'", op.From, op.Path)
+		}
+		if tree, err = from.Remove(tree); err != nil {
+			return nil, err
+		}
+		return addValue(tree, path, value)
+	}
+
+	return nil, fmt.Errorf("Unknown op '%s'", op.Op)
+}
+
+// addValue implements the add operation, which unlike Pointer.Set inserts
+// into arrays instead of replacing elements
+func addValue(tree interface{}, path Pointer, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := path[:len(path)-1].Resolve(tree)
+	if _, ok := parent.([]interface{}); !ok || err != nil {
+		return path.Set(tree, value)
+	}
+
+	return path.change(tree, func(container interface{}) (interface{}, error) {
+		array := container.([]interface{})
+		index, err := path.index(len(path)-1, len(array), true)
+		if err != nil {
+			return nil, err
+		}
+
+		inserted := make([]interface{}, 0, len(array)+1)
+		inserted = append(inserted, array[:index]...)
+		inserted = append(inserted, value)
+		return append(inserted, array[index:]...), nil
+	})
+}
+
+func isPointerPrefix(prefix, ptr Pointer) bool {
+	for i := range prefix {
+		if prefix[i] != ptr[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Diff returns a patch that turns original into modified. Objects are
+// compared member by member, arrays element by element along their longest
+// common subsequence, so inserted or removed elements don't cause the rest
+// of the array to be replaced
+func Diff(original, modified interface{}) Patch {
+	return diffValues(Pointer{}, original, modified, nil)
+}
+
+func diffValues(path Pointer, original, modified interface{}, patch Patch) Patch {
+	if jsonEqual(original, modified) {
+		return patch
+	}
+
+	switch original := original.(type) {
+	case map[string]interface{}:
+		if modified, ok := modified.(map[string]interface{}); ok {
+			return diffObjects(path, original, modified, patch)
+		}
+	case []interface{}:
+		if modified, ok := modified.([]interface{}); ok {
+			return diffArrays(path, original, modified, patch)
+		}
+	}
+
+	return append(patch, Operation{Op: "replace", Path: path.String(), Value: deepCopy(modified)})
+}
+
+func diffObjects(path Pointer, original, modified map[string]interface{}, patch Patch) Patch {
+	for _, key := range sortedKeys(original) {
+		if _, ok := modified[key]; !ok {
+			patch = append(patch, Operation{Op: "remove", Path: childPointer(path, key).String()})
+		}
+	}
+
+	for _, key := range sortedKeys(modified) {
+		if value, ok := original[key]; ok {
+			patch = diffValues(childPointer(path, key), value, modified[key], patch)
+		} else {
+			patch = append(patch, Operation{Op: "add", Path: childPointer(path, key).String(), Value: deepCopy(modified[key])})
+		}
+	}
+
+	return patch
+}
+
+// diffArrays walks the edit script of both arrays. Runs of removed and added
+// elements between kept ones are paired up and diffed recursively, the rest
+// is removed or added
+func diffArrays(path Pointer, original, modified []interface{}, patch Patch) Patch {
+	// common[i][j] is the length of the longest common subsequence of
+	// original[i:] and modified[j:]
+	common := make([][]int, len(original)+1)
+	for i := range common {
+		common[i] = make([]int, len(modified)+1)
+	}
+	for i := len(original) - 1; i >= 0; i-- {
+		for j := len(modified) - 1; j >= 0; j-- {
+			if jsonEqual(original[i], modified[j]) {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = common[i+1][j]
+				if common[i][j+1] > common[i][j] {
+					common[i][j] = common[i][j+1]
+				}
+			}
+		}
+	}
+
+	// index is the position in the array as it is after the operations so far
+	index := 0
+	var removed, added []interface{}
+	flush := func() {
+		paired := len(removed)
+		if len(added) < paired {
+			paired = len(added)
+		}
+		for k := 0; k < paired; k++ {
+			patch = diffValues(childPointer(path, strconv.Itoa(index)), removed[k], added[k], patch)
+			index++
+		}
+		for range removed[paired:] {
+			patch = append(patch, Operation{Op: "remove", Path: childPointer(path, strconv.Itoa(index)).String()})
+		}
+		for _, value := range added[paired:] {
+			patch = append(patch, Operation{Op: "add", Path: childPointer(path, strconv.Itoa(index)).String(), Value: deepCopy(value)})
+			index++
+		}
+		removed, added = removed[:0], added[:0]
+	}
+
+	i, j := 0, 0
+	for i < len(original) || j < len(modified) {
+		switch {
+		case i < len(original) && j < len(modified) && jsonEqual(original[i], modified[j]):
+			flush()
+			index++
+			i++
+			j++
+		case j == len(modified) || (i < len(original) && common[i+1][j] >= common[i][j+1]):
+			removed = append(removed, original[i])
+			i++
+		default:
+			added = append(added, modified[j])
+			j++
+		}
+	}
+	flush()
+
+	return patch
+}
+
+func childPointer(path Pointer, token string) Pointer {
+	child := make(Pointer, len(path), len(path)+1)
+	copy(child, path)
+	return append(child, token)
+}
+
+// jsonEqual compares generic values by their json meaning, so numbers of
+// different representations are equal if their values are exactly the same
+func jsonEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, value := range a {
+			other, ok := b[key]
+			if !ok || !jsonEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch a.(type) {
+	case float64, int64, Number, *big.Int, *big.Float:
+		return numberEqual(a, b)
+	}
+	return compareEqual(a, true, b, true)
+}
+
+// numberEqual compares numbers without rounding them, unless one of them
+// already is a float64. Distinct integers beyond 2^53 stay distinct
+func numberEqual(a, b interface{}) bool {
+	_, lFloat := a.(float64)
+	_, rFloat := b.(float64)
+	if lFloat || rFloat {
+		l, lOk := roundedValue(a)
+		r, rOk := roundedValue(b)
+		return lOk && rOk && l == r
+	}
+
+	l, lOk := exactValue(a)
+	r, rOk := exactValue(b)
+	return lOk && rOk && l.Cmp(r) == 0
+}
+
+// roundedValue converts a number to the nearest float64
+func roundedValue(value interface{}) (float64, bool) {
+	if f, ok := value.(float64); ok {
+		return f, true
+	}
+
+	exact, ok := exactValue(value)
+	if !ok {
+		return 0, false
+	}
+	f, _ := exact.Float64()
+	return f, true
+}
+
+// exactValue converts a number that isn't a float64 to a rational
+func exactValue(value interface{}) (*big.Rat, bool) {
+	switch number := value.(type) {
+	case int64:
+		return new(big.Rat).SetInt64(number), true
+	case Number:
+		if number == "" {
+			// like for Marshal, the zero Number is 0
+			return new(big.Rat), true
+		}
+		return new(big.Rat).SetString(string(number))
+	case *big.Int:
+		return new(big.Rat).SetInt(number), true
+	case *big.Float:
+		if number.IsInf() {
+			return nil, false
+		}
+		r, _ := number.Rat(nil)
+		return r, true
+	}
+
+	return nil, false
+}
+
+// deepCopy copies all objects and arrays of a generic value
+func deepCopy(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(value))
+		for key, member := range value {
+			copied[key] = deepCopy(member)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(value))
+		for i, element := range value {
+			copied[i] = deepCopy(element)
+		}
+		return copied
+	}
+
+	return value
+}
@@ -0,0 +1,237 @@
+package gogojson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func applyPatch(t *testing.T, document, patch string) (interface{}, error) {
+	tree, err := GogoJson(document)
+	assert.Nil(t, err, document)
+	ops, err := ParsePatch(patch)
+	assert.Nil(t, err, patch)
+
+	return ops.Apply(tree)
+}
+
+func TestPatchApply(t *testing.T) {
+	assert := assert.New(t)
+
+	// examples of RFC 6902, appendix A
+	cases := []struct{ document, patch, expected string }{
+		{`{"foo": "bar"}`, `[{"op": "add", "path": "/baz", "value": "qux"}]`, `{"baz": "qux", "foo": "bar"}`},
+		{`{"foo": ["bar", "baz"]}`, `[{"op": "add", "path": "/foo/1", "value": "qux"}]`, `{"foo": ["bar", "qux", "baz"]}`},
+		{`{"baz": "qux", "foo": "bar"}`, `[{"op": "remove", "path": "/baz"}]`, `{"foo": "bar"}`},
+		{`{"foo": ["bar", "qux", "baz"]}`, `[{"op": "remove", "path": "/foo/1"}]`, `{"foo": ["bar", "baz"]}`},
+		{`{"baz": "qux", "foo": "bar"}`, `[{"op": "replace", "path": "/baz", "value": "boo"}]`, `{"baz": "boo", "foo": "bar"}`},
+		{`{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			`[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			`{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`},
+		{`{"foo": ["all", "grass", "cows", "eat"]}`, `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			`{"foo": ["all", "cows", "eat", "grass"]}`},
+		{`{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			`[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}]`,
+			`{"baz": "qux", "foo": ["a", 2, "c"]}`},
+		{`{"foo": "bar"}`, `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`,
+			`{"foo": "bar", "child": {"grandchild": {}}}`},
+		{`{"foo": ["bar"]}`, `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`, `{"foo": ["bar", ["abc", "def"]]}`},
+		{`{"foo": null}`, `[{"op": "test", "path": "/foo", "value": null}]`, `{"foo": null}`},
+		{`{"foo": {"a": [1, 2]}}`, `[{"op": "copy", "from": "/foo", "path": "/bar"}, {"op": "add", "path": "/bar/a/0", "value": 0}]`,
+			`{"foo": {"a": [1, 2]}, "bar": {"a": [0, 1, 2]}}`},
+		{`[1, 2]`, `[{"op": "replace", "path": "", "value": {"a": 1}}]`, `{"a": 1}`},
+		{`{"a": [[1]]}`, `[{"op": "add", "path": "/a/0/0", "value": 0}]`, `{"a": [[0, 1]]}`},
+	}
+	for _, c := range cases {
+		result, err := applyPatch(t, c.document, c.patch)
+		assert.Nil(err, c.patch)
+		expected, _ := GogoJson(c.expected)
+		assert.Equal(expected, result, c.patch)
+	}
+}
+
+func TestPatchApplyErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := map[string]string{
+		`[{"op": "add", "path": "/baz/bat", "value": "qux"}]`:                                    `patch operation 0 (add): Member 'baz' not found (pointer "/baz/bat")`,
+		`[{"op": "test", "path": "/foo", "value": ["bar"]}, {"op": "remove", "path": "/foo/1"}]`: `patch operation 1 (remove): Index 1 is out of range for an array of length 1 (pointer "/foo/1")`,
+		`[{"op": "replace", "path": "/missing", "value": 1}]`:                                    `patch operation 0 (replace): Member 'missing' not found (pointer "/missing")`,
+		`[{"op": "test", "path": "/foo/0", "value": "baz"}]`:                                     `patch operation 0 (test): Test failed, the value at '/foo/0' differs`,
+		`[{"op": "move", "from": "/foo", "path": "/foo/0"}]`:                                     `patch operation 0 (move): Cannot move '/foo' into its own child '/foo/0'`,
+		`[{"op": "copy", "from": "/bar", "path": "/baz"}]`:                                       `patch operation 0 (copy): Member 'bar' not found (pointer "/bar")`,
+		`[{"op": "add", "path": "/foo/02", "value": 1}]`:                                         `patch operation 0 (add): Invalid array index '02' (pointer "/foo/02")`,
+		`[{"op": "add", "path": "foo", "value": 1}]`:                                             `patch operation 0 (add): Pointer must be empty or start with '/' (pointer "foo")`,
+	}
+	for patch, expected := range cases {
+		_, err := applyPatch(t, `{"foo": ["bar"]}`, patch)
+		assert.EqualError(err, expected, patch)
+		assert.IsType(&PatchError{}, err, patch)
+	}
+}
+
+func TestPatchApplyIsAtomic(t *testing.T) {
+	assert := assert.New(t)
+	tree, _ := GogoJson(`{"a": {"b": [1, 2]}, "c": true}`)
+	original, _ := GogoJson(`{"a": {"b": [1, 2]}, "c": true}`)
+
+	patch, _ := ParsePatch(`[
+		{"op": "add", "path": "/a/x", "value": 1},
+		{"op": "remove", "path": "/c"},
+		{"op": "replace", "path": "/a/b/0", "value": 0},
+		{"op": "test", "path": "/a/b/1", "value": 3}
+	]`)
+	result, err := patch.Apply(tree)
+	assert.Nil(result)
+	assert.Equal(3, err.(*PatchError).Index)
+	assert.Equal(original, tree)
+
+	// values of the patch are copied, not shared with the result
+	patch = Patch{{Op: "add", Path: "/d", Value: map[string]interface{}{}}}
+	result, err = patch.Apply(tree)
+	assert.Nil(err)
+	result.(map[string]interface{})["d"].(map[string]interface{})["x"] = 1.0
+	assert.Equal(map[string]interface{}{}, patch[0].Value)
+	assert.Equal(original, tree)
+}
+
+func TestParsePatch(t *testing.T) {
+	assert := assert.New(t)
+
+	patch, err := ParsePatch(`[{"op": "add", "path": "/a", "value": null}, {"op": "move", "from": "/a", "path": "/b", "value": 1}]`)
+	assert.Nil(err)
+	assert.Equal(Patch{{Op: "add", Path: "/a"}, {Op: "move", Path: "/b", From: "/a"}}, patch)
+
+	cases := map[string]string{
+		`{"op": "add"}`:                 "A patch has to be an array of operations",
+		`[1]`:                           "patch operation 0 (): Operation has to be an object",
+		`[{"path": "/a"}]`:              "patch operation 0 (): Missing member 'op'",
+		`[{"op": "remove"}]`:            "patch operation 0 (remove): Missing member 'path'",
+		`[{"op": "add", "path": "/a"}]`: "patch operation 0 (add): Missing member 'value'",
+		`[{"op": "copy", "path": "/a", "from": 1}]`:                        "patch operation 0 (copy): Member 'from' has to be a string",
+		`[{"op": "remove", "path": "/a"}, {"op": "delete", "path": "/a"}]`: "patch operation 1 (delete): Unknown op 'delete'",
+		`[{"op": "add", "path": "/a", "value": tru}]`:                      `Invalid literal 'tru', expected true, false or null (line 1, column 39, offset 38)`,
+	}
+	for source, expected := range cases {
+		_, err := ParsePatch(source)
+		assert.EqualError(err, expected, source)
+	}
+}
+
+func TestPatchMarshal(t *testing.T) {
+	assert := assert.New(t)
+
+	patch := Patch{
+		{Op: "add", Path: "/a", Value: nil},
+		{Op: "remove", Path: "/b", Value: 1.0},
+		{Op: "copy", Path: "/c", From: "/d"},
+		{Op: "test", Path: "/e", Value: []interface{}{"x"}},
+	}
+	out, err := Marshal(patch)
+	assert.Nil(err)
+	assert.Equal(`[{"op":"add","path":"/a","value":null},{"op":"remove","path":"/b"},`+
+		`{"op":"copy","path":"/c","from":"/d"},{"op":"test","path":"/e","value":["x"]}]`, string(out))
+
+	var decoded Patch
+	assert.Nil(Unmarshal(out, &decoded))
+	assert.Equal(Patch{{Op: "add", Path: "/a"}, {Op: "remove", Path: "/b"}, {Op: "copy", Path: "/c", From: "/d"},
+		{Op: "test", Path: "/e", Value: []interface{}{"x"}}}, decoded)
+
+	assert.NotNil(Unmarshal([]byte(`[{"op": "replace", "path": "/a"}]`), &decoded))
+}
+
+func TestDiff(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := []struct{ original, modified, expected string }{
+		{`{"a": 1, "b": [1, 2]}`, `{"a": 1, "b": [1, 2]}`, `[]`},
+		{`{"a": 1, "b": 2}`, `{"b": 3, "c": {"d": null}}`,
+			`[{"op": "remove", "path": "/a"}, {"op": "replace", "path": "/b", "value": 3}, {"op": "add", "path": "/c", "value": {"d": null}}]`},
+		{`{"a": {"b~/": [true]}}`, `{"a": {"b~/": [false]}}`, `[{"op": "replace", "path": "/a/b~0~1/0", "value": false}]`},
+		{`[1, 2, 3, 4, 5]`, `[0, 1, 2, 4, 5, 6]`,
+			`[{"op": "add", "path": "/0", "value": 0}, {"op": "remove", "path": "/3"}, {"op": "add", "path": "/5", "value": 6}]`},
+		{`[{"id": 1, "tags": ["a"]}, {"id": 2}]`, `[{"id": 1, "tags": ["a", "b"]}, {"id": 2}]`,
+			`[{"op": "add", "path": "/0/tags/1", "value": "b"}]`},
+		{`[1, 2, 3]`, `[]`, `[{"op": "remove", "path": "/0"}, {"op": "remove", "path": "/0"}, {"op": "remove", "path": "/0"}]`},
+		{`{"a": [1]}`, `{"a": {"0": 1}}`, `[{"op": "replace", "path": "/a", "value": {"0": 1}}]`},
+		{`1`, `"1"`, `[{"op": "replace", "path": "", "value": "1"}]`},
+	}
+	for _, c := range cases {
+		original, _ := GogoJson(c.original)
+		modified, _ := GogoJson(c.modified)
+		expected, err := ParsePatch(c.expected)
+		assert.Nil(err)
+
+		patch := Diff(original, modified)
+		assert.Equal(len(expected), len(patch), c.modified)
+		for i := range expected {
+			assert.Equal(expected[i], patch[i], c.modified)
+		}
+
+		result, err := patch.Apply(original)
+		assert.Nil(err, c.modified)
+		assert.Equal(modified, result, c.modified)
+	}
+
+	// numbers are compared by value, not by representation
+	original, _ := ParseOptions{Numbers: NumberString}.GogoJson(`{"a": [1.0, 2]}`)
+	modified, _ := GogoJson(`{"a": [1, 2]}`)
+	assert.Empty(Diff(original, modified))
+}
+
+func TestDiffRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	documents := []string{
+		`{"name": "a", "items": [{"id": 1}, {"id": 2}, {"id": 3}], "meta": {"tags": ["x", "y"]}}`,
+		`{"name": "b", "items": [{"id": 3}, {"id": 1, "new": true}], "meta": {"tags": ["y", "x", "z"]}, "extra": [[]]}`,
+		`{"items": [], "meta": null}`,
+		`[{"items": [1, 2, 3]}, 4, "five"]`,
+		`[4, {"items": [3, 2, 1]}, "five", "six"]`,
+	}
+	for _, from := range documents {
+		for _, to := range documents {
+			original, _ := GogoJson(from)
+			modified, _ := GogoJson(to)
+			result, err := Diff(original, modified).Apply(original)
+			assert.Nil(err, from+" -> "+to)
+			assert.Equal(modified, result, from+" -> "+to)
+		}
+	}
+}
+
+func TestPatchExactNumbers(t *testing.T) {
+	assert := assert.New(t)
+
+	// 2^53 + 1 and 2^53 are the same float64
+	original := map[string]interface{}{"id": int64(9007199254740993)}
+	modified := map[string]interface{}{"id": int64(9007199254740992)}
+	assert.Equal(Patch{{Op: "replace", Path: "/id", Value: int64(9007199254740992)}}, Diff(original, modified))
+
+	for _, mode := range []NumberMode{NumberString, NumberInt64, NumberBig} {
+		opts := ParseOptions{Numbers: mode}
+		original, _ := opts.GogoJson(`{"ids": [9007199254740993, 1.5]}`)
+		modified, _ := opts.GogoJson(`{"ids": [9007199254740992, 1.5]}`)
+
+		patch := Diff(original, modified)
+		assert.Len(patch, 1, mode)
+		result, err := patch.Apply(original)
+		assert.Nil(err)
+		assert.Equal(modified, result)
+
+		test, err := opts.ParsePatch(`[{"op": "test", "path": "/ids/0", "value": 9007199254740992}]`)
+		assert.Nil(err)
+		_, err = test.Apply(original)
+		assert.EqualError(err, "patch operation 0 (test): Test failed, the value at '/ids/0' differs")
+		test, _ = opts.ParsePatch(`[{"op": "test", "path": "/ids/0", "value": 9007199254740993}]`)
+		_, err = test.Apply(original)
+		assert.Nil(err)
+	}
+
+	// different representations of the same number are equal
+	assert.True(jsonEqual(Number("1.50"), 1.5))
+	assert.True(jsonEqual(Number("1e2"), int64(100)))
+	assert.True(jsonEqual(Number(""), int64(0)))
+	assert.False(jsonEqual(Number("9007199254740993"), Number("9007199254740992")))
+	assert.False(jsonEqual(int64(1), "1"))
+}
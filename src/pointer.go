@@ -0,0 +1,222 @@
+package gogojson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pointer is a JSON pointer as defined by RFC 6901, stored as its unescaped
+// reference tokens. The empty pointer refers to the whole document, and
+// Pointer{"items", "3", "name"} to the same value as "/items/3/name"
+type Pointer []string
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// ParsePointer parses the string representation of a pointer, like
+// "/items/3/name". Malformed pointers are reported as a *PointerError
+func ParsePointer(s string) (Pointer, error) {
+	if s == "" {
+		return Pointer{}, nil
+	}
+	if s[0] != '/' {
+		return nil, &PointerError{Msg: "Pointer must be empty or start with '/'", Pointer: s}
+	}
+
+	tokens := strings.Split(s[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, &PointerError{Msg: "Invalid escape sequence, '~' must be followed by 0 or 1", Pointer: s}
+			}
+		}
+		tokens[i] = pointerUnescaper.Replace(token)
+	}
+
+	return Pointer(tokens), nil
+}
+
+// String returns the escaped representation of the pointer
+func (ptr Pointer) String() string {
+	var out strings.Builder
+	for _, token := range ptr {
+		out.WriteByte('/')
+		out.WriteString(pointerEscaper.Replace(token))
+	}
+
+	return out.String()
+}
+
+// Resolve returns the value the pointer refers to in a parsed tree.
+// Pointers to missing values are reported as a *PointerError
+func (ptr Pointer) Resolve(tree interface{}) (interface{}, error) {
+	current := tree
+	for i, token := range ptr {
+		switch container := current.(type) {
+		case map[string]interface{}:
+			value, ok := container[token]
+			if !ok {
+				return nil, ptr.errorf("Member '%s' not found", token)
+			}
+			current = value
+		case []interface{}:
+			index, err := ptr.index(i, len(container), false)
+			if err != nil {
+				return nil, err
+			}
+			current = container[index]
+		default:
+			return nil, ptr.errorf("Cannot look up '%s' in a %s value", token, jsonType(current))
+		}
+	}
+
+	return current, nil
+}
+
+// Set stores value at the location the pointer refers to and returns the
+// resulting tree. Existing values are replaced, new object members added,
+// and the index "-" or the length of an array appends to it. The parent of
+// the location has to exist. Objects are changed in place, arrays may be
+// reallocated, which is why the result has to be used instead of tree
+func (ptr Pointer) Set(tree interface{}, value interface{}) (interface{}, error) {
+	if len(ptr) == 0 {
+		return value, nil
+	}
+
+	return ptr.change(tree, func(container interface{}) (interface{}, error) {
+		last := len(ptr) - 1
+		switch container := container.(type) {
+		case map[string]interface{}:
+			container[ptr[last]] = value
+			return container, nil
+		case []interface{}:
+			index, err := ptr.index(last, len(container), true)
+			if err != nil {
+				return nil, err
+			}
+			if index == len(container) {
+				return append(container, value), nil
+			}
+			container[index] = value
+			return container, nil
+		}
+
+		return nil, ptr.errorf("Cannot set '%s' in a %s value", ptr[last], jsonType(container))
+	})
+}
+
+// Remove deletes the value the pointer refers to from a parsed tree and
+// returns the resulting tree. Array elements after the removed one shift
+// down. Like for Set, the result has to be used instead of tree
+func (ptr Pointer) Remove(tree interface{}) (interface{}, error) {
+	if len(ptr) == 0 {
+		return nil, ptr.errorf("Cannot remove the whole document")
+	}
+
+	return ptr.change(tree, func(container interface{}) (interface{}, error) {
+		last := len(ptr) - 1
+		switch container := container.(type) {
+		case map[string]interface{}:
+			if _, ok := container[ptr[last]]; !ok {
+				return nil, ptr.errorf("Member '%s' not found", ptr[last])
+			}
+			delete(container, ptr[last])
+			return container, nil
+		case []interface{}:
+			index, err := ptr.index(last, len(container), false)
+			if err != nil {
+				return nil, err
+			}
+			removed := make([]interface{}, 0, len(container)-1)
+			removed = append(removed, container[:index]...)
+			return append(removed, container[index+1:]...), nil
+		}
+
+		return nil, ptr.errorf("Cannot remove '%s' from a %s value", ptr[last], jsonType(container))
+	})
+}
+
+// change applies fn to the object or array holding the value the pointer
+// refers to, and stores the container fn returns in its place
+func (ptr Pointer) change(tree interface{}, fn func(container interface{}) (interface{}, error)) (interface{}, error) {
+	parent := ptr[:len(ptr)-1]
+	container, err := parent.Resolve(tree)
+	if err != nil {
+		return nil, ptr.wrap(err)
+	}
+
+	changed, err := fn(container)
+	if err != nil {
+		return nil, err
+	}
+	if len(parent) == 0 {
+		return changed, nil
+	}
+
+	// a reallocated array has to be stored in its parent again
+	if _, ok := changed.([]interface{}); ok {
+		return parent.Set(tree, changed)
+	}
+
+	return tree, nil
+}
+
+// index parses the array index at token i. With appending set, "-" and the
+// length of the array are accepted too and refer to the end of it
+func (ptr Pointer) index(i int, length int, appending bool) (int, error) {
+	token := ptr[i]
+	if token == "-" {
+		if appending {
+			return length, nil
+		}
+		return 0, ptr.errorf("Index '-' refers past the end of the array")
+	}
+
+	// leading zeros and signs are not allowed
+	if token == "" || (token[0] == '0' && len(token) > 1) || strings.Trim(token, "0123456789") != "" {
+		return 0, ptr.errorf("Invalid array index '%s'", token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index > length || (index == length && !appending) {
+		return 0, ptr.errorf("Index %s is out of range for an array of length %d", token, length)
+	}
+
+	return index, nil
+}
+
+func (ptr Pointer) errorf(format string, args ...interface{}) error {
+	return &PointerError{Msg: fmt.Sprintf(format, args...), Pointer: ptr.String()}
+}
+
+// wrap turns an error of a parent pointer into one naming ptr
+func (ptr Pointer) wrap(err error) error {
+	if pointerErr, ok := err.(*PointerError); ok {
+		return &PointerError{Msg: pointerErr.Msg, Pointer: ptr.String()}
+	}
+
+	return err
+}
+
+// jsonType names the json type of a generic value
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+
+	if _, ok := floatValue(value); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,175 @@
+package gogojson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const pointerJSON = `{
+  "foo": ["bar", "baz"],
+  "": 0,
+  "a/b": 1,
+  "c%d": 2,
+  "e^f": 3,
+  "g|h": 4,
+  "i\\j": 5,
+  "k\"l": 6,
+  " ": 7,
+  "m~n": 8
+}`
+
+func TestParsePointer(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := map[string]Pointer{
+		"":        {},
+		"/":       {""},
+		"/foo/0":  {"foo", "0"},
+		"/a~1b":   {"a/b"},
+		"/m~0n":   {"m~n"},
+		"/~01":    {"~1"},
+		"/~10":    {"/0"},
+		"/a//b/-": {"a", "", "b", "-"},
+	}
+	for s, expected := range cases {
+		ptr, err := ParsePointer(s)
+		assert.Nil(err, s)
+		assert.Equal(expected, ptr, s)
+		assert.Equal(s, ptr.String())
+	}
+
+	for _, s := range []string{"foo", "#/foo", "/a~", "/a~2", "/~/"} {
+		ptr, err := ParsePointer(s)
+		assert.Nil(ptr, s)
+		assert.IsType(&PointerError{}, err, s)
+	}
+
+	_, err := ParsePointer("/a~b")
+	assert.EqualError(err, `Invalid escape sequence, '~' must be followed by 0 or 1 (pointer "/a~b")`)
+}
+
+func TestPointerResolve(t *testing.T) {
+	assert := assert.New(t)
+	tree, err := GogoJson(pointerJSON)
+	assert.Nil(err)
+
+	// the examples of RFC 6901
+	cases := map[string]interface{}{
+		"":       tree,
+		"/foo":   []interface{}{"bar", "baz"},
+		"/foo/0": "bar",
+		"/":      float64(0),
+		"/a~1b":  float64(1),
+		"/c%d":   float64(2),
+		"/e^f":   float64(3),
+		"/g|h":   float64(4),
+		"/i\\j":  float64(5),
+		"/k\"l":  float64(6),
+		"/ ":     float64(7),
+		"/m~0n":  float64(8),
+	}
+	for s, expected := range cases {
+		ptr, err := ParsePointer(s)
+		assert.Nil(err)
+		value, err := ptr.Resolve(tree)
+		assert.Nil(err, s)
+		assert.Equal(expected, value, s)
+	}
+
+	errors := map[string]string{
+		"/missing":                  `Member 'missing' not found (pointer "/missing")`,
+		"/foo/2":                    `Index 2 is out of range for an array of length 2 (pointer "/foo/2")`,
+		"/foo/-":                    `Index '-' refers past the end of the array (pointer "/foo/-")`,
+		"/foo/01":                   `Invalid array index '01' (pointer "/foo/01")`,
+		"/foo/-1":                   `Invalid array index '-1' (pointer "/foo/-1")`,
+		"/foo/0/x":                  `Cannot look up 'x' in a string value (pointer "/foo/0/x")`,
+		"/ /x":                      `Cannot look up 'x' in a number value (pointer "/ /x")`,
+		"/foo/99999999999999999999": `Index 99999999999999999999 is out of range for an array of length 2 (pointer "/foo/99999999999999999999")`,
+	}
+	for s, expected := range errors {
+		ptr, _ := ParsePointer(s)
+		_, err := ptr.Resolve(tree)
+		assert.EqualError(err, expected, s)
+	}
+}
+
+func TestPointerSet(t *testing.T) {
+	assert := assert.New(t)
+	tree, _ := GogoJson(`{"a": {"b": [1, 2]}, "c": null}`)
+
+	tree, err := Pointer{"a", "b", "1"}.Set(tree, "two")
+	assert.Nil(err)
+	tree, err = Pointer{"a", "b", "-"}.Set(tree, 3.0)
+	assert.Nil(err)
+	tree, err = Pointer{"a", "b", "3"}.Set(tree, 4.0)
+	assert.Nil(err)
+	tree, err = Pointer{"a", "new"}.Set(tree, true)
+	assert.Nil(err)
+	tree, err = Pointer{"c"}.Set(tree, map[string]interface{}{})
+	assert.Nil(err)
+
+	expected, _ := GogoJson(`{"a": {"b": [1, "two", 3, 4], "new": true}, "c": {}}`)
+	assert.Equal(expected, tree)
+
+	_, err = Pointer{"a", "b", "5"}.Set(tree, 1.0)
+	assert.EqualError(err, `Index 5 is out of range for an array of length 4 (pointer "/a/b/5")`)
+	_, err = Pointer{"x", "y"}.Set(tree, 1.0)
+	assert.EqualError(err, `Member 'x' not found (pointer "/x/y")`)
+	_, err = Pointer{"a", "new", "x"}.Set(tree, 1.0)
+	assert.EqualError(err, `Cannot set 'x' in a boolean value (pointer "/a/new/x")`)
+
+	root, err := Pointer{}.Set(tree, "replaced")
+	assert.Nil(err)
+	assert.Equal("replaced", root)
+
+	array, err := Pointer{"-"}.Set([]interface{}{}, "appended")
+	assert.Nil(err)
+	assert.Equal([]interface{}{"appended"}, array)
+}
+
+func TestPointerRemove(t *testing.T) {
+	assert := assert.New(t)
+	tree, _ := GogoJson(`{"a": {"b": [1, 2, 3]}, "c": null}`)
+
+	tree, err := Pointer{"a", "b", "0"}.Remove(tree)
+	assert.Nil(err)
+	tree, err = Pointer{"c"}.Remove(tree)
+	assert.Nil(err)
+
+	expected, _ := GogoJson(`{"a": {"b": [2, 3]}}`)
+	assert.Equal(expected, tree)
+
+	_, err = Pointer{"c"}.Remove(tree)
+	assert.EqualError(err, `Member 'c' not found (pointer "/c")`)
+	_, err = Pointer{"a", "b", "-"}.Remove(tree)
+	assert.EqualError(err, `Index '-' refers past the end of the array (pointer "/a/b/-")`)
+	_, err = Pointer{}.Remove(tree)
+	assert.EqualError(err, `Cannot remove the whole document (pointer "")`)
+
+	array, err := Pointer{"1"}.Remove([]interface{}{1.0, 2.0})
+	assert.Nil(err)
+	assert.Equal([]interface{}{1.0}, array)
+}
+
+func TestSyntaxErrorPointer(t *testing.T) {
+	assert := assert.New(t)
+
+	cases := map[string]Pointer{
+		`{"a": {"b~/": [1, {"c": tru}]}}`: {"a", "b~/", "1", "c"},
+		`{"a": [1, 2 3]}`:                 {"a"},
+		`[[], [[0, -]]]`:                  {"1", "0", "1"},
+		`{"a" 1}`:                         nil,
+		`tru`:                             nil,
+	}
+	for source, expected := range cases {
+		_, err := GogoJson(source)
+		syntaxErr, ok := err.(*SyntaxError)
+		if assert.True(ok, source) {
+			assert.Equal(expected, syntaxErr.Pointer, source)
+		}
+	}
+
+	_, err := GogoJson(`{"a": {"b~/": [1, {"c": tru}]}}`)
+	assert.Equal("/a/b~0~1/1/c", err.(*SyntaxError).Pointer.String())
+}
@@ -0,0 +1,177 @@
+package gogojson
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// queryStep is either an object key or an array index of a query path
+type queryStep struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// Get looks up a value in a parsed tree by a path like "a.b[3].c", where
+// dots separate object keys and brackets index arrays. Dots, brackets and
+// backslashes that are part of a key are escaped with a backslash, so
+// "a\.b" is the key "a.b". The empty path returns the tree itself. Get
+// reports false for malformed paths and for values that don't exist,
+// including when a path expects an object or array but finds another type
+func Get(tree interface{}, path string) (interface{}, bool) {
+	steps, err := parseQuery(path)
+	if err != nil {
+		return nil, false
+	}
+
+	current := tree
+	for _, step := range steps {
+		if step.isIndex {
+			array, ok := current.([]interface{})
+			if !ok || step.index >= len(array) {
+				return nil, false
+			}
+			current = array[step.index]
+		} else {
+			object, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if current, ok = object[step.key]; !ok {
+				return nil, false
+			}
+		}
+	}
+
+	return current, true
+}
+
+// GetString works like Get, but also reports false for values that aren't
+// strings
+func GetString(tree interface{}, path string) (string, bool) {
+	value, _ := Get(tree, path)
+	s, ok := value.(string)
+	return s, ok
+}
+
+// GetFloat works like Get, but also reports false for values that aren't
+// numbers. Numbers of any NumberMode are converted to float64
+func GetFloat(tree interface{}, path string) (float64, bool) {
+	value, _ := Get(tree, path)
+	return floatValue(value)
+}
+
+// GetBool works like Get, but also reports false for values that aren't
+// booleans
+func GetBool(tree interface{}, path string) (bool, bool) {
+	value, _ := Get(tree, path)
+	b, ok := value.(bool)
+	return b, ok
+}
+
+// GetMap works like Get, but also reports false for values that aren't
+// objects
+func GetMap(tree interface{}, path string) (map[string]interface{}, bool) {
+	value, _ := Get(tree, path)
+	m, ok := value.(map[string]interface{})
+	return m, ok
+}
+
+// GetSlice works like Get, but also reports false for values that aren't
+// arrays
+func GetSlice(tree interface{}, path string) ([]interface{}, bool) {
+	value, _ := Get(tree, path)
+	s, ok := value.([]interface{})
+	return s, ok
+}
+
+// floatValue converts a number of any NumberMode to float64
+func floatValue(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case float64:
+		return number, true
+	case int64:
+		return float64(number), true
+	case Number:
+		f, err := number.Float64()
+		return f, err == nil
+	case *big.Int:
+		f, _ := number.Float64()
+		return f, true
+	case *big.Float:
+		f, _ := number.Float64()
+		return f, true
+	}
+
+	return 0, false
+}
+
+// parseQuery splits a path into its steps. Malformed paths are reported as
+// a *PathSyntaxError
+func parseQuery(path string) ([]queryStep, error) {
+	p := &pathParser{expr: path}
+	var steps []queryStep
+
+	for p.pos < len(path) {
+		if p.consume("[") {
+			start := p.pos
+			for p.pos < len(path) && path[p.pos] >= '0' && path[p.pos] <= '9' {
+				p.pos++
+			}
+			if p.pos == start {
+				return nil, p.unexpected("an index")
+			}
+
+			digits := path[start:p.pos]
+			index, err := strconv.Atoi(digits)
+			if err != nil {
+				p.pos = start
+				return nil, p.errorf("Invalid index '%s'", digits)
+			}
+			if !p.consume("]") {
+				return nil, p.unexpected("']'")
+			}
+			steps = append(steps, queryStep{index: index, isIndex: true})
+			continue
+		}
+
+		// keys after the first step are separated by a dot
+		if len(steps) > 0 && !p.consume(".") {
+			return nil, p.unexpected("'.' or '['")
+		}
+		key, err := p.parseQueryKey()
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, queryStep{key: key})
+	}
+
+	return steps, nil
+}
+
+// parseQueryKey reads an object key up to the next unescaped dot or bracket
+func (p *pathParser) parseQueryKey() (string, error) {
+	var key strings.Builder
+
+	start := p.pos
+	for p.pos < len(p.expr) && p.expr[p.pos] != '.' && p.expr[p.pos] != '[' {
+		switch p.expr[p.pos] {
+		case ']':
+			return "", p.errorf("Unescaped ']' in key")
+		case '\\':
+			p.pos++
+			if p.pos == len(p.expr) || !strings.ContainsRune(`.[]\`, rune(p.expr[p.pos])) {
+				p.pos--
+				return "", p.errorf("Invalid escape sequence in key")
+			}
+		}
+		key.WriteByte(p.expr[p.pos])
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.unexpected("a key")
+	}
+
+	return key.String(), nil
+}
@@ -0,0 +1,119 @@
+package gogojson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const queryJSON = `{
+  "name": "Peter",
+  "kids": [
+    {"name": "Meg", "age": 17, "pets": []},
+    {"name": "Chris", "age": 15.5},
+    {"name": "Stewie", "age": 1, "evil": true}
+  ],
+  "address": {"city": "Quahog", "zip": null},
+  "dotted.key": {"[x]": 1, "back\\slash": 2},
+  "matrix": [[1, 2], [3, 4]]
+}`
+
+func TestGet(t *testing.T) {
+	assert := assert.New(t)
+	tree, err := GogoJson(queryJSON)
+	assert.Nil(err)
+
+	cases := map[string]interface{}{
+		"name":                    "Peter",
+		"kids[1].name":            "Chris",
+		"kids[2].evil":            true,
+		"kids[0].pets":            []interface{}{},
+		"address.zip":             nil,
+		`dotted\.key.\[x\]`:       float64(1),
+		`dotted\.key.back\\slash`: float64(2),
+		"matrix[1][0]":            float64(3),
+	}
+	for path, expected := range cases {
+		value, ok := Get(tree, path)
+		assert.True(ok, path)
+		assert.Equal(expected, value, path)
+	}
+
+	value, ok := Get(tree, "")
+	assert.True(ok)
+	assert.Equal(tree, value)
+
+	array, _ := GogoJson(`[{"a": 1}]`)
+	value, ok = Get(array, "[0].a")
+	assert.True(ok)
+	assert.Equal(float64(1), value)
+}
+
+func TestGetMissing(t *testing.T) {
+	assert := assert.New(t)
+	tree, err := GogoJson(queryJSON)
+	assert.Nil(err)
+
+	paths := []string{
+		// values that don't exist
+		"unknown", "kids[3]", "kids[0].evil", "name.first", "kids.name", "matrix[0][2]",
+		"address.zip.code", "dotted.key",
+		// malformed paths
+		".name", "name.", "kids..name", "kids[]", "kids[-1]", "kids[a]", "kids[0", "kids[0]name",
+		"kids]", `name\`, `na\me`, "kids[99999999999999999999]",
+	}
+	for _, path := range paths {
+		value, ok := Get(tree, path)
+		assert.False(ok, path)
+		assert.Nil(value, path)
+	}
+
+	_, ok := Get(nil, "a")
+	assert.False(ok)
+}
+
+func TestGetTyped(t *testing.T) {
+	assert := assert.New(t)
+	tree, err := GogoJson(queryJSON)
+	assert.Nil(err)
+
+	name, ok := GetString(tree, "kids[2].name")
+	assert.True(ok)
+	assert.Equal("Stewie", name)
+	_, ok = GetString(tree, "kids[2].age")
+	assert.False(ok)
+
+	age, ok := GetFloat(tree, "kids[1].age")
+	assert.True(ok)
+	assert.Equal(15.5, age)
+	_, ok = GetFloat(tree, "name")
+	assert.False(ok)
+
+	evil, ok := GetBool(tree, "kids[2].evil")
+	assert.True(ok && evil)
+	_, ok = GetBool(tree, "kids[1].evil")
+	assert.False(ok)
+
+	address, ok := GetMap(tree, "address")
+	assert.True(ok)
+	assert.Equal("Quahog", address["city"])
+	_, ok = GetMap(tree, "address.zip")
+	assert.False(ok)
+
+	kids, ok := GetSlice(tree, "kids")
+	assert.True(ok)
+	assert.Len(kids, 3)
+	_, ok = GetSlice(tree, "kids[0]")
+	assert.False(ok)
+
+	for _, mode := range []NumberMode{NumberString, NumberInt64, NumberBig} {
+		tree, err := ParseOptions{Numbers: mode}.GogoJson(queryJSON)
+		assert.Nil(err)
+		age, ok := GetFloat(tree, "kids[0].age")
+		assert.True(ok)
+		assert.Equal(float64(17), age)
+		age, ok = GetFloat(tree, "kids[1].age")
+		assert.True(ok)
+		assert.Equal(15.5, age)
+	}
+}
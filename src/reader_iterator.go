@@ -0,0 +1,118 @@
+package gogojson
+
+import (
+	"bufio"
+	"io"
+)
+
+// ReaderIterator is an Iterator over the utf-8 characters of an io.Reader.
+// The input is read in buffered chunks, so it never has to fit into memory as
+// a whole
+type ReaderIterator struct {
+	reader *bufio.Reader
+	// next character and its size in bytes, "" if it hasn't been read yet
+	peeked string
+	size   uint64
+	err    error
+	offset uint64
+	line   uint64
+	row    uint64
+	opts   IteratorOptions
+}
+
+// fill reads the next character into peeked unless it is there already
+func (iter *ReaderIterator) fill() {
+	if iter.peeked != "" || iter.err != nil {
+		return
+	}
+
+	r, size, err := iter.reader.ReadRune()
+	if err != nil {
+		iter.err = err
+		return
+	}
+
+	// skip a leading byte order mark
+	if r == '\uFEFF' && iter.offset == 0 {
+		iter.offset = uint64(size)
+		iter.fill()
+		return
+	}
+
+	iter.peeked, iter.err = iter.opts.decode(r, size, iter.GetPosition())
+	iter.size = uint64(size)
+}
+
+func (iter *ReaderIterator) Next() string {
+	iter.fill()
+	char := iter.peeked
+	if char == "" {
+		return ""
+	}
+
+	iter.peeked = ""
+	pos := iter.GetPosition()
+	iter.opts.advance(&pos, char, iter.size)
+	iter.line, iter.row, iter.offset = pos.Line, pos.Column, pos.Offset
+
+	return char
+}
+
+func (iter *ReaderIterator) Peek() string {
+	iter.fill()
+	return iter.peeked
+}
+
+func (iter *ReaderIterator) Eof() bool {
+	return iter.Peek() == ""
+}
+
+func (iter *ReaderIterator) HasNext() bool {
+	return !iter.Eof()
+}
+
+// Err returns the error of the underlying reader or the *SyntaxError for
+// invalid utf-8, the end of input is not reported as one
+func (iter *ReaderIterator) Err() error {
+	if iter.err == io.EOF {
+		return nil
+	}
+
+	return iter.err
+}
+
+func (iter *ReaderIterator) GetLine() uint64 {
+	return iter.line
+}
+
+func (iter *ReaderIterator) GetRow() uint64 {
+	return iter.row
+}
+
+func (iter *ReaderIterator) GetOffset() uint64 {
+	return iter.offset
+}
+
+// GetPosition returns the position of the next character
+func (iter *ReaderIterator) GetPosition() Position {
+	return Position{
+		Line:   iter.line,
+		Column: iter.row,
+		Offset: iter.offset,
+	}
+}
+
+func MakeReaderIterator(reader io.Reader) *ReaderIterator {
+	return IteratorOptions{}.MakeReaderIterator(reader)
+}
+
+// MakeReaderIterator works like the package level MakeReaderIterator, but
+// applies the options. A leading byte order mark is skipped
+func (opts IteratorOptions) MakeReaderIterator(reader io.Reader) *ReaderIterator {
+	return &ReaderIterator{
+		reader: bufio.NewReader(reader),
+		line:   1,
+		row:    1,
+		opts:   opts,
+	}
+}
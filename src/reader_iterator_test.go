@@ -0,0 +1,86 @@
+package gogojson
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReaderIterator(t *testing.T) {
+	assert := assert.New(t)
+	iterator := MakeReaderIterator(iotest.OneByteReader(strings.NewReader(newlineString)))
+
+	assert.Equal(iterator.Peek(), "a")
+	assert.Equal(iterator.Next(), "a")
+	assert.Equal(iterator.Next(), "b")
+	assert.Equal(Position{Line: 1, Column: 3, Offset: 2}, iterator.GetPosition())
+
+	assert.Equal(iterator.Next(), "\n")
+	assert.Equal(Position{Line: 2, Column: 1, Offset: 3}, iterator.GetPosition())
+
+	assert.Equal(iterator.Next(), "c")
+	assert.Equal(iterator.GetRow(), uint64(2))
+	assert.Equal(iterator.GetLine(), uint64(2))
+	assert.False(iterator.HasNext())
+
+	assert.Equal(iterator.Next(), "")
+	assert.Equal(iterator.Peek(), "")
+	assert.True(iterator.Eof())
+	assert.Nil(iterator.Err())
+}
+
+func TestReaderIteratorTokenize(t *testing.T) {
+	assert := assert.New(t)
+	reader := iotest.HalfReader(strings.NewReader(parseJSON))
+	tokens, err := Tokenize(MakeReaderIterator(reader))
+	assert.Nil(err)
+
+	expected, _ := Tokenize(MakeIterator(parseJSON))
+	assert.Equal(expected, tokens)
+}
+
+func TestReaderIteratorError(t *testing.T) {
+	assert := assert.New(t)
+	failure := errors.New("connection reset")
+	reader := io.MultiReader(strings.NewReader(`{"name": "Pet`), iotest.ErrReader(failure))
+
+	iterator := MakeReaderIterator(reader)
+	_, err := Tokenize(iterator)
+
+	assert.Equal(failure, err)
+	assert.Equal(failure, iterator.Err())
+	assert.True(iterator.Eof())
+
+	// the error is reported even if everything read so far was valid
+	_, err = Tokenize(MakeReaderIterator(io.MultiReader(strings.NewReader(`[1, 2`), iotest.ErrReader(failure))))
+	assert.Equal(failure, err)
+}
+
+func TestReaderIteratorUTF8(t *testing.T) {
+	assert := assert.New(t)
+	iterator := MakeReaderIterator(iotest.OneByteReader(strings.NewReader("\uFEFFä€\n😀")))
+
+	// the reader is only touched once the first character is needed
+	assert.Equal(iterator.Peek(), "ä")
+	assert.Equal(Position{Line: 1, Column: 1, Offset: 3}, iterator.GetPosition())
+	assert.Equal(iterator.Next(), "ä")
+	assert.Equal(iterator.Next(), "€")
+	assert.Equal(Position{Line: 1, Column: 3, Offset: 8}, iterator.GetPosition())
+	assert.Equal(iterator.Next(), "\n")
+	assert.Equal(iterator.Next(), "😀")
+	assert.Equal(Position{Line: 2, Column: 2, Offset: 13}, iterator.GetPosition())
+
+	_, err := Tokenize(MakeReaderIterator(strings.NewReader("\"\xff\"")))
+	assert.Equal(&SyntaxError{
+		Msg:      "Invalid UTF-8 encoding",
+		Position: Position{Line: 1, Column: 2, Offset: 1},
+	}, err)
+
+	tokens, err := Tokenize(IteratorOptions{ReplaceInvalidUTF8: true}.MakeReaderIterator(strings.NewReader("\"\xff\"")))
+	assert.Nil(err)
+	assert.Equal("�", tokens[0].Value)
+}
@@ -1,14 +1,20 @@
 package gogojson
 
 import (
-	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
 )
 
 type Token struct {
 	Type  string
 	Value interface{}
+	// Raw is the literal text of NUM tokens, whose Value is nil when the
+	// number doesn't fit a float64
+	Raw string
+	Position
 }
 
 const PUNC = "PUNC"
@@ -20,38 +26,82 @@ const NULL = "NULL"
 // define all regexes for parsing
 var punctuationRegex = regexp.MustCompile("[{}:,\\[\\]]")
 
-// check for true, false and null
-var identifierRegex = regexp.MustCompile("[truefalsn]")
+// true, false and null, the whole word is read so misspellings can be reported
+var identifierRegex = regexp.MustCompile("[a-zA-Z]")
+var numberInitRegex = regexp.MustCompile("[-0-9]")
 var numberRegex = regexp.MustCompile("[0-9]")
 var stringInitRegex = regexp.MustCompile("\"")
-var stringBodyRegex = regexp.MustCompile("[^\"]")
 var whitespaceRegex = regexp.MustCompile("[\n\t\r ]")
 
-func Tokenize(iter *StringIterator) []*Token {
+// lexer adds the methods to read single tokens to any Iterator
+type lexer struct {
+	Iterator
+}
+
+// Tokenize reads the whole iterator and returns the list of tokens it is made
+// of. A *SyntaxError is returned for input that can't be tokenized, errors
+// of the iterator itself are returned as they are
+func Tokenize(source Iterator) ([]*Token, error) {
+	iter := lexer{source}
 	tokens := make([]*Token, 0)
-	nextToken := func(next string) *Token {
-		if isPunctuation(next) {
-			return iter.ParsePunctuation(next)
-		} else if isStringInit(next) {
-			return iter.ParseString(next)
-		} else if isIdentifier(next) {
-			return iter.ParseIdentifier(next)
-		} else if isNumber(next) {
-			return iter.ParseNumber(next)
+
+	for {
+		token, err := iter.nextToken()
+		if err != nil {
+			return nil, err
+		} else if token == nil {
+			return tokens, nil
 		}
 
-		panic(fmt.Sprintf("Unexpected character type: '%s'", next))
+		tokens = append(tokens, token)
+	}
+}
+
+// nextToken reads the next token, which is nil at the end of input
+func (iter lexer) nextToken() (*Token, error) {
+	token := new(Token)
+	if ok, err := iter.readToken(token); !ok || err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// readToken reads the next token into token, which allows reusing its
+// memory. It returns false at the end of input
+func (iter lexer) readToken(token *Token) (bool, error) {
+	// skip whitespace as we don't care about it
+	iter.skipWhitespace()
+	if iter.Eof() {
+		return false, iter.Err()
 	}
 
-	for iter.HasNext() {
-		// skip whitespace as we don't care about it
-		iter.SkipWhitespace()
+	pos := iter.GetPosition()
+	next := iter.Next()
 
-		next := iter.Next()
-		tokens = append(tokens, nextToken(next))
+	var err error
+	if isPunctuation(next) {
+		*token = iter.parsePunctuation(next)
+	} else if isStringInit(next) {
+		*token, err = iter.parseString(next)
+	} else if isIdentifier(next) {
+		*token, err = iter.parseIdentifier(next)
+	} else if isNumberInit(next) {
+		*token, err = iter.parseNumber(next)
+	} else {
+		err = newSyntaxError(pos, "Unexpected character type: '%s'", next)
 	}
 
-	return tokens
+	if err != nil {
+		// a failing reader looks like the end of input to the lexer
+		if readErr := iter.Err(); readErr != nil {
+			return false, readErr
+		}
+		return false, err
+	}
+
+	token.Position = pos
+	return true, nil
 }
 
 // Character classification
@@ -63,14 +113,14 @@ func isStringInit(check string) bool {
 	return stringInitRegex.MatchString(check)
 }
 
-func isStringBody(check string) bool {
-	return !stringInitRegex.MatchString(check)
-}
-
 func isIdentifier(check string) bool {
 	return identifierRegex.MatchString(check)
 }
 
+func isNumberInit(check string) bool {
+	return numberInitRegex.MatchString(check)
+}
+
 func isNumber(check string) bool {
 	return numberRegex.MatchString(check)
 }
@@ -79,64 +129,237 @@ func isWhitespace(check string) bool {
 	return whitespaceRegex.MatchString(check)
 }
 
-// iterator parse methods
-func (json *StringIterator) ParsePunctuation(init string) *Token {
-	return &Token{
+// lexer parse methods
+func (json lexer) parsePunctuation(init string) Token {
+	return Token{
 		Value: init,
 		Type:  PUNC,
 	}
 }
 
-func (json *StringIterator) ParseString(init string) *Token {
-	str := ""
-	for isStringBody(json.Peek()) {
-		str += json.Next()
-	}
+// parseString reads a string up to its closing quote and decodes all escape
+// sequences, including utf-16 surrogate pairs
+func (json lexer) parseString(init string) (Token, error) {
+	var str strings.Builder
+	// high surrogate waiting for its low half
+	var high rune
+
+	for {
+		pos := json.GetPosition()
+		if json.Eof() {
+			return Token{}, newSyntaxError(pos, "Unterminated string")
+		}
+
+		char := json.Next()
+		if char != "\\" && high != 0 {
+			// lone surrogates are replaced like any other invalid character
+			str.WriteRune(unicode.ReplacementChar)
+			high = 0
+		}
 
-	// skip closing
-	json.Next()
+		if isStringInit(char) {
+			break
+		} else if char == "\\" {
+			r, err := json.parseEscape(pos)
+			if err != nil {
+				return Token{}, err
+			}
+			high = writeEscaped(&str, high, r)
+		} else if char[0] < 0x20 {
+			return Token{}, newSyntaxError(pos, "Invalid control character %q in string", char)
+		} else {
+			str.WriteString(char)
+		}
+	}
 
-	return &Token{
-		Value: str,
+	return Token{
+		Value: str.String(),
 		Type:  STRING,
+	}, nil
+}
+
+// writeEscaped writes an escaped rune while combining surrogate pairs. It
+// returns the high surrogate that still waits for its low half
+func writeEscaped(str *strings.Builder, high rune, r rune) rune {
+	if high != 0 {
+		if r >= 0xdc00 && r <= 0xdfff {
+			str.WriteRune(utf16.DecodeRune(high, r))
+			return 0
+		}
+		str.WriteRune(unicode.ReplacementChar)
+	}
+
+	if r >= 0xd800 && r <= 0xdbff {
+		return r
 	}
+	// a low surrogate without high half ends up as a replacement char
+	str.WriteRune(r)
+	return 0
 }
 
-func (json *StringIterator) ParseNumber(init string) *Token {
-	for isNumber(json.Peek()) {
-		init += json.Next()
+var escapes = map[string]rune{
+	"\"": '"',
+	"\\": '\\',
+	"/":  '/',
+	"b":  '\b',
+	"f":  '\f',
+	"n":  '\n',
+	"r":  '\r',
+	"t":  '\t',
+}
+
+// parseEscape decodes the escape sequence following a backslash at pos.
+// Surrogates of \u escapes are returned as they are
+func (json lexer) parseEscape(pos Position) (rune, error) {
+	char := json.Next()
+	if r, ok := escapes[char]; ok {
+		return r, nil
+	} else if char == "u" {
+		return json.parseHex(pos)
+	}
+
+	return 0, newSyntaxError(pos, "Invalid escape sequence '\\%s'", char)
+}
+
+// parseHex reads the four hex digits of a \u escape
+func (json lexer) parseHex(pos Position) (rune, error) {
+	digits := ""
+	for i := 0; i < 4; i++ {
+		digits += json.Next()
+	}
+
+	r, err := strconv.ParseUint(digits, 16, 16)
+	if err != nil || len(digits) != 4 {
+		return 0, newSyntaxError(pos, "Invalid unicode escape '\\u%s'", digits)
 	}
 
-	num, _ := strconv.ParseFloat(init, 32)
+	return rune(r), nil
+}
+
+// parseNumber reads a number following the json grammar: an optional minus,
+// an integer part without leading zeros, an optional fraction and an optional
+// exponent
+func (json lexer) parseNumber(init string) (Token, error) {
+	literal := init
+	if init == "-" {
+		if !isNumber(json.Peek()) {
+			return Token{}, newSyntaxError(json.GetPosition(), "Expected digit after '-'")
+		}
+		literal += json.Next()
+	}
+
+	// integer part
+	if literal[len(literal)-1] == '0' {
+		if isNumber(json.Peek()) {
+			return Token{}, newSyntaxError(json.GetPosition(), "Invalid leading zero in number '%s'", literal+json.Peek())
+		}
+	} else {
+		literal += json.readDigits()
+	}
+
+	// fraction
+	if json.Peek() == "." {
+		literal += json.Next()
+		if !isNumber(json.Peek()) {
+			return Token{}, newSyntaxError(json.GetPosition(), "Expected digit after '.' in number '%s'", literal)
+		}
+		literal += json.readDigits()
+	}
 
-	// TODO: make it support floats
-	return &Token{
-		Value: num,
-		Type:  NUM,
+	// exponent
+	if json.Peek() == "e" || json.Peek() == "E" {
+		literal += json.Next()
+		if json.Peek() == "+" || json.Peek() == "-" {
+			literal += json.Next()
+		}
+		if !isNumber(json.Peek()) {
+			return Token{}, newSyntaxError(json.GetPosition(), "Expected digit in exponent of number '%s'", literal)
+		}
+		literal += json.readDigits()
+	}
+
+	// literals beyond float64 stay valid here, only the value is left out
+	token := Token{Type: NUM, Raw: literal}
+	if num, err := strconv.ParseFloat(literal, 64); err == nil {
+		token.Value = num
+	}
+
+	return token, nil
+}
+
+func (json lexer) readDigits() string {
+	digits := ""
+	for isNumber(json.Peek()) {
+		digits += json.Next()
 	}
+
+	return digits
 }
 
-func (json *StringIterator) ParseIdentifier(init string) *Token {
+// parseIdentifier reads one of the literals true, false and null
+func (json lexer) parseIdentifier(init string) (Token, error) {
+	// init is a single ascii letter, so the literal starts one byte back
+	pos := json.GetPosition()
+	pos.Column--
+	pos.Offset--
+
 	for isIdentifier(json.Peek()) {
 		init += json.Next()
 	}
 
-	if init == "true" || init == "false" {
-		return &Token{
+	switch init {
+	case "true", "false":
+		return Token{
 			Value: init == "true",
 			Type:  BOOL,
-		}
+		}, nil
+	case "null":
+		return Token{
+			Value: nil,
+			Type:  NULL,
+		}, nil
 	}
 
-	return &Token{
-		Value: nil,
-		Type:  NULL,
-	}
+	return Token{}, newSyntaxError(pos, "Invalid literal '%s', expected true, false or null", init)
 }
 
 // TODO: improve this maybe?
-func (json *StringIterator) SkipWhitespace() {
+func (json lexer) skipWhitespace() {
 	for isWhitespace(json.Peek()) {
 		json.Next()
 	}
 }
+
+// ParsePunctuation returns the token for a punctuation character
+func (json *StringIterator) ParsePunctuation(init string) *Token {
+	token := lexer{json}.parsePunctuation(init)
+	return &token
+}
+
+// ParseString reads the rest of a string whose opening quote is init
+func (json *StringIterator) ParseString(init string) (*Token, error) {
+	return tokenOrError(lexer{json}.parseString(init))
+}
+
+// ParseNumber reads the rest of a number starting with init
+func (json *StringIterator) ParseNumber(init string) (*Token, error) {
+	return tokenOrError(lexer{json}.parseNumber(init))
+}
+
+// ParseIdentifier reads the rest of a literal starting with init
+func (json *StringIterator) ParseIdentifier(init string) (*Token, error) {
+	return tokenOrError(lexer{json}.parseIdentifier(init))
+}
+
+// SkipWhitespace consumes whitespace up to the next token
+func (json *StringIterator) SkipWhitespace() {
+	lexer{json}.skipWhitespace()
+}
+
+func tokenOrError(token Token, err error) (*Token, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
@@ -6,17 +6,18 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-var json = `{
+var tokenizeJSON = `{
   "hello": 123,
   "how": {},
   "lies": true
 }`
 
 func TestTokenize(t *testing.T) {
-	iterator := MakeIterator(json)
-	tokens := Tokenize(iterator)
+	iterator := MakeIterator(tokenizeJSON)
+	tokens, err := Tokenize(iterator)
 
 	assert := assert.New(t)
+	assert.Nil(err)
 
 	assert.Equal(PUNC, tokens[0].Type, "Type is not PUNC")
 	assert.Equal(STRING, tokens[1].Type, "Type is not STRING")
@@ -31,12 +32,186 @@ func TestTokenize(t *testing.T) {
 func TestTokenizeNil(t *testing.T) {
 	assert := assert.New(t)
 
-	defer func() {
-		if r := recover(); r != nil {
-			assert.Equal(r, "Unexpected character type: '!'")
+	iterator := MakeIterator(`  !`)
+	tokens, err := Tokenize(iterator)
+
+	assert.Nil(tokens)
+	assert.Equal(&SyntaxError{
+		Msg:      "Unexpected character type: '!'",
+		Position: Position{Line: 1, Column: 3, Offset: 2},
+	}, err)
+}
+
+func TestTokenizePosition(t *testing.T) {
+	assert := assert.New(t)
+	tokens, err := Tokenize(MakeIterator(tokenizeJSON))
+
+	assert.Nil(err)
+	assert.Equal(Position{Line: 1, Column: 1, Offset: 0}, tokens[0].Position)
+	assert.Equal(Position{Line: 2, Column: 3, Offset: 4}, tokens[1].Position)
+	assert.Equal(Position{Line: 4, Column: 11, Offset: 41}, tokens[len(tokens)-2].Position)
+}
+
+func TestTokenizeUnterminatedString(t *testing.T) {
+	assert := assert.New(t)
+	_, err := Tokenize(MakeIterator(`{"abc`))
+
+	assert.Equal(&SyntaxError{
+		Msg:      "Unterminated string",
+		Position: Position{Line: 1, Column: 6, Offset: 5},
+	}, err)
+}
+
+func TestTokenizeStringEscapes(t *testing.T) {
+	assert := assert.New(t)
+	strings := map[string]string{
+		`"a\"b"`:               `a"b`,
+		`"\\ \/ \b\f\n\r\t"`:   "\\ / \b\f\n\r\t",
+		`"caf\u00e9"`:          "café",
+		`"\u00E9\u4e2d"`:       "é中",
+		`"\ud83d\ude00!"`:      "😀!",
+		`"\ud83d"`:             "\ufffd",
+		`"\ud83dx"`:            "\ufffdx",
+		`"\ud83d\n"`:           "\ufffd\n",
+		`"\ude00"`:             "\ufffd",
+		`"\ud83d\ud83d\ude00"`: "\ufffd😀",
+	}
+
+	for source, expected := range strings {
+		tokens, err := Tokenize(MakeIterator(source))
+
+		assert.Nil(err, source)
+		assert.Equal(expected, tokens[0].Value, source)
+	}
+}
+
+func TestTokenizeStringErrors(t *testing.T) {
+	assert := assert.New(t)
+	errors := map[string]*SyntaxError{
+		`"a\x"`: {
+			Msg:      "Invalid escape sequence '\\x'",
+			Position: Position{Line: 1, Column: 3, Offset: 2},
+		},
+		`"\u12g4"`: {
+			Msg:      "Invalid unicode escape '\\u12g4'",
+			Position: Position{Line: 1, Column: 2, Offset: 1},
+		},
+		`"\u12`: {
+			Msg:      "Invalid unicode escape '\\u12'",
+			Position: Position{Line: 1, Column: 2, Offset: 1},
+		},
+		"\"a\tb\"": {
+			Msg:      "Invalid control character \"\\t\" in string",
+			Position: Position{Line: 1, Column: 3, Offset: 2},
+		},
+		"\"a\nb\"": {
+			Msg:      "Invalid control character \"\\n\" in string",
+			Position: Position{Line: 1, Column: 3, Offset: 2},
+		},
+		`"abc\"`: {
+			Msg:      "Unterminated string",
+			Position: Position{Line: 1, Column: 7, Offset: 6},
+		},
+	}
+
+	for source, expected := range errors {
+		_, err := Tokenize(MakeIterator(source))
+
+		assert.Equal(expected, err, source)
+	}
+}
+
+func TestTokenizeNumbers(t *testing.T) {
+	assert := assert.New(t)
+	numbers := map[string]float64{
+		`0`:                   0,
+		`-0`:                  0,
+		`7`:                   7,
+		`-12`:                 -12,
+		`0.1`:                 0.1,
+		`-3.25`:               -3.25,
+		`1e3`:                 1000,
+		`1E+3`:                1000,
+		`25e-2`:               0.25,
+		`-0.5e1`:              -5,
+		`9007199254740993`:    9007199254740992,
+		`123456789.123456789`: 123456789.123456789,
+		`1e-400`:              0,
+	}
+
+	for source, expected := range numbers {
+		tokens, err := Tokenize(MakeIterator(source))
+
+		assert.Nil(err, source)
+		assert.Len(tokens, 1, source)
+		assert.Equal(expected, tokens[0].Value, source)
+	}
+}
+
+func TestTokenizeNumberErrors(t *testing.T) {
+	assert := assert.New(t)
+	errors := map[string]string{
+		`-`:    "Expected digit after '-'",
+		`-a`:   "Expected digit after '-'",
+		`01`:   "Invalid leading zero in number '01'",
+		`-00`:  "Invalid leading zero in number '-00'",
+		`1.`:   "Expected digit after '.' in number '1.'",
+		`1.e5`: "Expected digit after '.' in number '1.'",
+		`1e`:   "Expected digit in exponent of number '1e'",
+		`1e+`:  "Expected digit in exponent of number '1e+'",
+	}
+
+	for source, expected := range errors {
+		_, err := Tokenize(MakeIterator(source))
+
+		assert.IsType(&SyntaxError{}, err, source)
+		if err != nil {
+			assert.Equal(expected, err.(*SyntaxError).Msg, source)
 		}
-	}()
+	}
+}
+
+func TestTokenizeLiterals(t *testing.T) {
+	assert := assert.New(t)
+	tokens, err := Tokenize(MakeIterator(`[true, false, null]`))
+
+	assert.Nil(err)
+	assert.Equal(&Token{Type: BOOL, Value: true, Position: Position{Line: 1, Column: 2, Offset: 1}}, tokens[1])
+	assert.Equal(&Token{Type: BOOL, Value: false, Position: Position{Line: 1, Column: 8, Offset: 7}}, tokens[3])
+	assert.Equal(&Token{Type: NULL, Value: nil, Position: Position{Line: 1, Column: 15, Offset: 14}}, tokens[5])
+}
+
+func TestTokenizeLiteralErrors(t *testing.T) {
+	assert := assert.New(t)
+	literals := []string{"nul", "tru", "flase", "sss", "True", "NULL", "nulll", "falsey", "n", "x"}
+
+	for _, literal := range literals {
+		_, err := Tokenize(MakeIterator(`{"a": ` + literal + `}`))
+
+		assert.Equal(&SyntaxError{
+			Msg:      "Invalid literal '" + literal + "', expected true, false or null",
+			Position: Position{Line: 1, Column: 7, Offset: 6},
+		}, err, literal)
+	}
+}
+
+func TestStringIteratorLexerMethods(t *testing.T) {
+	assert := assert.New(t)
+	iter := MakeIterator(`  "a\n" 12.5e1 null`)
+
+	iter.SkipWhitespace()
+	token, err := iter.ParseString(iter.Next())
+	assert.Nil(err)
+	assert.Equal("a\n", token.Value)
+
+	iter.SkipWhitespace()
+	token, err = iter.ParseNumber(iter.Next())
+	assert.Nil(err)
+	assert.Equal(125.0, token.Value)
 
-	iterator := MakeIterator(`!`)
-	Tokenize(iterator)
+	iter.SkipWhitespace()
+	token, err = iter.ParseIdentifier(iter.Next())
+	assert.Nil(err)
+	assert.Equal(NULL, token.Type)
+	assert.Equal(PUNC, iter.ParsePunctuation("{").Type)
 }